@@ -0,0 +1,136 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Schedules everything a boat needs and works out which crates fill first
+type BoatOrderPlanner struct {
+	repo      *GoodsRepository
+	scheduler *ProductionScheduler
+}
+
+func NewBoatOrderPlanner(repo *GoodsRepository) *BoatOrderPlanner {
+	return &BoatOrderPlanner{
+		repo:      repo,
+		scheduler: NewProductionScheduler(repo.GetAllGoods()),
+	}
+}
+
+func (p *BoatOrderPlanner) Plan(order models.BoatOrder, state models.PlayerState, now time.Time) (models.BoatOrderPlan, error) {
+	result := models.BoatOrderPlan{Crates: []models.CratePlan{}}
+
+	if !order.Deadline.After(now) {
+		return result, base.ErrDeadlinePassed
+	}
+	if err := validateInventory(p.repo, state.Inventory); err != nil {
+		return result, err
+	}
+
+	inventory := make(map[string]int)
+	for name, amount := range state.Inventory {
+		inventory[name] = amount
+	}
+
+	// Crate goods already in storage are packed straight away
+	fromStock := make(map[string]int)
+	units := make(map[string]int)
+	var goods models.HayDayGoodList
+	for _, crate := range order.Crates {
+		if crate.Amount < 1 {
+			return result, base.ErrInvalidQuantity
+		}
+		good, err := p.repo.GetGoodByName(crate.Good)
+		if err != nil {
+			return result, err
+		}
+		if _, seen := units[good.Name]; !seen {
+			goods = append(goods, *good)
+		}
+		units[good.Name] += crate.Amount
+		result.SaleValue += good.MaxPrice * crate.Amount
+	}
+
+	var plan models.PlannedGoodList
+	for _, good := range goods {
+		taken := min(inventory[good.Name], units[good.Name])
+		fromStock[good.Name] = taken
+		inventory[good.Name] -= taken
+
+		if missing := units[good.Name] - taken; missing > 0 {
+			plan = append(plan, models.PlannedGood{HayDayGood: good, Quantity: missing})
+		}
+	}
+
+	state.Inventory = inventory
+	state.Horizon = order.Deadline.Sub(now)
+	result.Schedule = p.scheduler.Schedule(plan, state, now)
+
+	readyTimes := p.readyTimes(result.Schedule, goods, fromStock, now)
+
+	result.CanComplete = true
+	for _, crate := range order.Crates {
+		cratePlan := models.CratePlan{Good: crate.Good, Amount: crate.Amount}
+
+		available := readyTimes[crate.Good]
+		if len(available) >= crate.Amount {
+			cratePlan.Ready = true
+			cratePlan.ReadyAt = &available[crate.Amount-1]
+			readyTimes[crate.Good] = available[crate.Amount:]
+		} else {
+			result.CanComplete = false
+		}
+
+		result.Crates = append(result.Crates, cratePlan)
+	}
+
+	// Fill whatever is ready first, crates that cannot be made go last
+	sort.SliceStable(result.Crates, func(i, j int) bool {
+		if result.Crates[i].Ready != result.Crates[j].Ready {
+			return result.Crates[i].Ready
+		}
+		return result.Crates[i].Ready && result.Crates[i].ReadyAt.Before(*result.Crates[j].ReadyAt)
+	})
+
+	result.RewardValue = order.Coins + int(math.Round(float64(order.XP)*order.CoinsPerXP))
+	result.Balance = result.RewardValue - result.SaleValue
+
+	return result, nil
+}
+
+// When every unit of the crate goods is in hand, stock first then scheduled jobs
+func (p *BoatOrderPlanner) readyTimes(schedule models.ProductionSchedule, goods models.HayDayGoodList, fromStock map[string]int, now time.Time) map[string][]time.Time {
+	wanted := make(map[string]bool)
+	for _, good := range goods {
+		wanted[good.Name] = true
+	}
+
+	readyTimes := make(map[string][]time.Time)
+	for name, amount := range fromStock {
+		for range amount {
+			readyTimes[name] = append(readyTimes[name], now)
+		}
+	}
+
+	finished := make(map[string][]time.Time)
+	for _, timeline := range schedule.Timelines {
+		for _, job := range timeline.Jobs {
+			if wanted[job.Good] {
+				finished[job.Good] = append(finished[job.Good], job.Finish)
+			}
+		}
+	}
+	for name, times := range finished {
+		sort.Slice(times, func(i, j int) bool {
+			return times[i].Before(times[j])
+		})
+		readyTimes[name] = append(readyTimes[name], times...)
+	}
+
+	return readyTimes
+}
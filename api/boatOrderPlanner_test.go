@@ -0,0 +1,78 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestBoatOrderPlannerRejectsInvalidInput(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	order := models.BoatOrder{
+		Level:    10,
+		Crates:   []models.BoatCrate{{Good: "Bread", Amount: 2}},
+		Deadline: now.Add(6 * time.Hour),
+	}
+
+	tests := []struct {
+		name      string
+		crates    []models.BoatCrate
+		inventory map[string]int
+		deadline  time.Time
+		want      error
+	}{
+		{name: "negative inventory", inventory: map[string]int{"Wheat": -4}, want: base.ErrInvalidInventory},
+		{name: "unknown inventory good", inventory: map[string]int{"Gold": 1}, want: base.ErrNoGoodByNameFound},
+		{name: "negative crate amount", crates: []models.BoatCrate{{Good: "Bread", Amount: -1}}, want: base.ErrInvalidQuantity},
+		{name: "empty crate", crates: []models.BoatCrate{{Good: "Bread", Amount: 0}}, want: base.ErrInvalidQuantity},
+		{name: "unknown crate good", crates: []models.BoatCrate{{Good: "Gold", Amount: 1}}, want: base.ErrNoGoodByNameFound},
+		{name: "deadline passed", deadline: now.Add(-time.Hour), want: base.ErrDeadlinePassed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := order
+			if test.crates != nil {
+				request.Crates = test.crates
+			}
+			if !test.deadline.IsZero() {
+				request.Deadline = test.deadline
+			}
+
+			_, err := NewBoatOrderPlanner(testRepository()).Plan(request, models.PlayerState{Level: 10, Inventory: test.inventory}, now)
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestBoatOrderPlannerWithoutRequiredSource(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	order := models.BoatOrder{
+		Crates:   []models.BoatCrate{{Good: "Bread", Amount: 1}, {Good: "Chicken feed", Amount: 1}},
+		Deadline: now.Add(6 * time.Hour),
+	}
+	// A feed mill and fields but no bakery
+	state := models.PlayerState{
+		Level:    10,
+		Capacity: map[string]int{models.SourceField: 6, models.SourceFeedMill: 1, "Chicken": 3},
+	}
+
+	plan, err := NewBoatOrderPlanner(testRepository()).Plan(order, state, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if plan.CanComplete {
+		t.Error("order without a bakery marked as completable")
+	}
+	for _, crate := range plan.Crates {
+		if wantReady := crate.Good != "Bread"; crate.Ready != wantReady {
+			t.Errorf("%s ready = %v, want %v", crate.Good, crate.Ready, wantReady)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type BOMExpander struct {
+	goodsMap  map[uuid.UUID]models.HayDayGood
+	leadTimes map[uuid.UUID]time.Duration
+}
+
+func NewBOMExpander(allGoods models.HayDayGoodList) *BOMExpander {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	for _, good := range allGoods {
+		goodsMap[good.ID] = good
+	}
+
+	return &BOMExpander{
+		goodsMap:  goodsMap,
+		leadTimes: make(map[uuid.UUID]time.Duration),
+	}
+}
+
+// Flatten the ingredient tree of a good into total amounts per product. Sources
+// that make a batch per run only need a set of ingredients per batch.
+func (e *BOMExpander) Expand(good models.HayDayGood, quantity int) models.BillOfMaterials {
+	batches := models.Batches(good.Source, quantity)
+	bom := models.BillOfMaterials{
+		Good:        good.Name,
+		Quantity:    quantity,
+		Items:       []models.BOMItem{},
+		MachineTime: make(map[string]time.Duration),
+		TotalXP:     good.GainedXP * batches,
+		LeadTime:    e.LeadTime(good),
+	}
+	bom.MachineTime[good.Source] += good.ProductionTime * time.Duration(batches)
+
+	amounts := make(map[uuid.UUID]int)
+	e.expandIngredients(good, quantity, amounts, make(map[uuid.UUID]bool))
+
+	for id, amount := range amounts {
+		ingredientGood := e.goodsMap[id]
+
+		bom.Items = append(bom.Items, models.BOMItem{
+			Name:          ingredientGood.Name,
+			Source:        ingredientGood.Source,
+			Amount:        amount,
+			IsBaseProduct: isBaseProduct(ingredientGood),
+		})
+		ingredientBatches := models.Batches(ingredientGood.Source, amount)
+		bom.MachineTime[ingredientGood.Source] += ingredientGood.ProductionTime * time.Duration(ingredientBatches)
+		bom.TotalXP += ingredientGood.GainedXP * ingredientBatches
+	}
+
+	sort.Slice(bom.Items, func(i, j int) bool {
+		return bom.Items[i].Name < bom.Items[j].Name
+	})
+
+	return bom
+}
+
+func (e *BOMExpander) expandIngredients(good models.HayDayGood, quantity int, amounts map[uuid.UUID]int, visited map[uuid.UUID]bool) {
+	// Prevent infinite recursion with cycles
+	if visited[good.ID] {
+		return
+	}
+	visited[good.ID] = true
+	defer delete(visited, good.ID)
+
+	for _, ingredient := range good.Ingredients {
+		ingredientGood, exists := e.goodsMap[ingredient.ProductID]
+		if !exists {
+			continue
+		}
+
+		needed := ingredient.Amount * models.Batches(good.Source, quantity)
+		amounts[ingredientGood.ID] += needed
+
+		e.expandIngredients(ingredientGood, needed, amounts, visited)
+	}
+}
+
+// End-to-end time to make a good from scratch: the longest path through its
+// ingredient tree, assuming every ingredient's source can run in parallel
+func (e *BOMExpander) LeadTime(good models.HayDayGood) time.Duration {
+	return e.leadTime(good, make(map[uuid.UUID]bool))
+}
+
+func (e *BOMExpander) leadTime(good models.HayDayGood, visited map[uuid.UUID]bool) time.Duration {
+	if leadTime, cached := e.leadTimes[good.ID]; cached {
+		return leadTime
+	}
+	// Prevent infinite recursion with cycles
+	if visited[good.ID] {
+		return 0
+	}
+	visited[good.ID] = true
+	defer delete(visited, good.ID)
+
+	var slowestIngredient time.Duration
+	for _, ingredient := range good.Ingredients {
+		ingredientGood, exists := e.goodsMap[ingredient.ProductID]
+		if !exists {
+			continue
+		}
+		slowestIngredient = max(slowestIngredient, e.leadTime(ingredientGood, visited))
+	}
+
+	leadTime := good.ProductionTime + slowestIngredient
+	e.leadTimes[good.ID] = leadTime
+	return leadTime
+}
+
+// Walk the ingredient tree like Expand but take whatever is in stock first, only
+// the shortfall of an ingredient has to be produced and is expanded further
+func (e *BOMExpander) ExpandNeeds(good models.HayDayGood, quantity int, stock map[uuid.UUID]int, needs map[uuid.UUID]*models.IngredientNeed) {
+	e.expandNeeds(good, quantity, stock, needs, make(map[uuid.UUID]bool))
+}
+
+func (e *BOMExpander) expandNeeds(good models.HayDayGood, quantity int, stock map[uuid.UUID]int, needs map[uuid.UUID]*models.IngredientNeed, visited map[uuid.UUID]bool) {
+	// Prevent infinite recursion with cycles
+	if visited[good.ID] || quantity <= 0 {
+		return
+	}
+	visited[good.ID] = true
+	defer delete(visited, good.ID)
+
+	for _, ingredient := range good.Ingredients {
+		ingredientGood, exists := e.goodsMap[ingredient.ProductID]
+		if !exists {
+			continue
+		}
+
+		needed := ingredient.Amount * models.Batches(good.Source, quantity)
+		fromStock := min(stock[ingredientGood.ID], needed)
+		stock[ingredientGood.ID] -= fromStock
+
+		need, tracked := needs[ingredientGood.ID]
+		if !tracked {
+			need = &models.IngredientNeed{Name: ingredientGood.Name}
+			needs[ingredientGood.ID] = need
+		}
+		need.Needed += needed
+		need.FromInventory += fromStock
+		need.ToProduce += needed - fromStock
+
+		e.expandNeeds(ingredientGood, needed-fromStock, stock, needs, visited)
+	}
+}
+
+// Ingredients from the included sources that the goods of a plan consume over
+// the horizon, together with the best earnings per hour of a good that
+// consumes each of them
+func planDemand(expander *BOMExpander, goods models.PlannedGoodList, include func(source string) bool) (map[string]int, map[string]float64) {
+	demand := make(map[string]int)
+	priority := make(map[string]float64)
+
+	for _, good := range goods {
+		if include(good.Source) || good.ProductionTime <= 0 {
+			continue
+		}
+
+		// Every strategy sizes its plan to the horizon
+		if good.Quantity == 0 {
+			continue
+		}
+
+		rate := float64(good.NetValue) / good.ProductionTime.Hours()
+		for _, item := range expander.Expand(good.HayDayGood, good.Quantity).Items {
+			if !include(item.Source) {
+				continue
+			}
+			demand[item.Name] += item.Amount
+			priority[item.Name] = max(priority[item.Name], rate)
+		}
+	}
+
+	return demand, priority
+}
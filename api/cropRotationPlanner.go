@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Splits the player's fields across crops. Fields first grow what the machines
+// of a strategy's plan consume over the horizon, crops feeding the most
+// profitable machines first, and whatever is left grows the crop that sells
+// for the most per field.
+type CropRotationPlanner struct {
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
+	expander   *BOMExpander
+}
+
+func NewCropRotationPlanner(repo *GoodsRepository, strategies *StrategyRegistry) *CropRotationPlanner {
+	return &CropRotationPlanner{
+		repo:       repo,
+		strategies: strategies,
+		expander:   NewBOMExpander(repo.GetAllGoods()),
+	}
+}
+
+func (p *CropRotationPlanner) Plan(ctx context.Context, state models.PlayerState, fields int, strategyName string) (models.FieldRotationPlan, error) {
+	if fields < 1 {
+		return models.FieldRotationPlan{}, base.ErrInvalidQuantity
+	}
+
+	horizon := state.Horizon
+	if horizon <= 0 {
+		horizon = defaultPlanningHorizon
+	}
+	state.Horizon = horizon
+
+	plan, err := runStrategy(ctx, p.strategies, strategyName, state)
+	if err != nil {
+		return models.FieldRotationPlan{}, err
+	}
+
+	crops := make(map[string]models.HayDayGood)
+	for _, good := range p.repo.GetGoodsByLevel(state.Level) {
+		if good.Source == models.SourceField && good.ProductionTime > 0 {
+			crops[good.Name] = good
+		}
+	}
+
+	demand, priority := planDemand(p.expander, plan.Goods, func(source string) bool {
+		return source == models.SourceField
+	})
+
+	rotation := models.FieldRotationPlan{
+		Fields:      fields,
+		Horizon:     horizon,
+		Strategy:    plan.Strategy,
+		Crops:       []models.CropAllocation{},
+		UnmetDemand: make(map[string]int),
+	}
+
+	demanded := make([]string, 0, len(demand))
+	for name := range demand {
+		demanded = append(demanded, name)
+	}
+	sort.Slice(demanded, func(i, j int) bool {
+		if priority[demanded[i]] != priority[demanded[j]] {
+			return priority[demanded[i]] > priority[demanded[j]]
+		}
+		return demanded[i] < demanded[j]
+	})
+
+	remaining := fields
+	allocations := make(map[string]*models.CropAllocation)
+	for _, name := range demanded {
+		crop, exists := crops[name]
+		perField := cropOutputPerField(crop, horizon)
+		if !exists || perField == 0 || remaining == 0 {
+			rotation.UnmetDemand[name] = demand[name]
+			continue
+		}
+
+		needed := (demand[name] + perField - 1) / perField
+		allocated := min(needed, remaining)
+		remaining -= allocated
+
+		allocations[name] = &models.CropAllocation{
+			Crop:             name,
+			Fields:           allocated,
+			HarvestsPerField: int(horizon / crop.ProductionTime),
+			Demand:           demand[name],
+		}
+	}
+
+	if best, found := bestSaleCrop(crops, horizon); found && remaining > 0 {
+		allocation, exists := allocations[best.Name]
+		if !exists {
+			allocation = &models.CropAllocation{
+				Crop:             best.Name,
+				HarvestsPerField: int(horizon / best.ProductionTime),
+			}
+			allocations[best.Name] = allocation
+		}
+		allocation.Fields += remaining
+		remaining = 0
+	}
+	rotation.UnusedFields = remaining
+
+	for name, allocation := range allocations {
+		crop := crops[name]
+		allocation.Output = allocation.Fields * cropOutputPerField(crop, horizon)
+		allocation.ForSale = max(0, allocation.Output-allocation.Demand)
+		if missing := allocation.Demand - allocation.Output; missing > 0 {
+			rotation.UnmetDemand[name] = missing
+		}
+		rotation.SaleCoins += allocation.ForSale * crop.MaxPrice
+		rotation.Crops = append(rotation.Crops, *allocation)
+	}
+
+	sort.Slice(rotation.Crops, func(i, j int) bool {
+		if rotation.Crops[i].Fields != rotation.Crops[j].Fields {
+			return rotation.Crops[i].Fields > rotation.Crops[j].Fields
+		}
+		return rotation.Crops[i].Crop < rotation.Crops[j].Crop
+	})
+
+	return rotation, nil
+}
+
+// Crops one field yields over the horizon after replanting
+func cropOutputPerField(crop models.HayDayGood, horizon time.Duration) int {
+	if crop.ProductionTime <= 0 {
+		return 0
+	}
+	return int(horizon/crop.ProductionTime) * (models.FieldYield - 1)
+}
+
+func bestSaleCrop(crops map[string]models.HayDayGood, horizon time.Duration) (models.HayDayGood, bool) {
+	var best models.HayDayGood
+	bestValue := 0
+	for _, crop := range crops {
+		value := cropOutputPerField(crop, horizon) * crop.MaxPrice
+		if value > bestValue || (value == bestValue && value > 0 && crop.Name < best.Name) {
+			best, bestValue = crop, value
+		}
+	}
+	return best, bestValue > 0
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestCropRotationPlannerRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   int
+		strategy string
+		want     error
+	}{
+		{name: "no fields", fields: 0, want: base.ErrInvalidQuantity},
+		{name: "negative fields", fields: -2, want: base.ErrInvalidQuantity},
+		{name: "unknown strategy", fields: 4, strategy: "nope", want: base.ErrUnknownStrategy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := testRepository()
+			planner := NewCropRotationPlanner(repo, NewDefaultStrategyRegistry(repo))
+
+			_, err := planner.Plan(context.Background(), models.PlayerState{Level: 5}, test.fields, test.strategy)
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestCropRotationPlannerServesMachinesFirst(t *testing.T) {
+	// The bakery bakes bread all day, its wheat comes first and the fields
+	// left over grow whatever sells best
+	state := models.PlayerState{
+		Level:    2,
+		Horizon:  24 * time.Hour,
+		Capacity: map[string]int{models.SourceField: 10, "Bakery": 1},
+	}
+	repo := testRepository()
+	planner := NewCropRotationPlanner(repo, NewDefaultStrategyRegistry(repo))
+
+	rotation, err := planner.Plan(context.Background(), state, 10, "greedy")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fields := make(map[string]models.CropAllocation)
+	total := 0
+	for _, allocation := range rotation.Crops {
+		fields[allocation.Crop] = allocation
+		total += allocation.Fields
+	}
+	if total != 10 || rotation.UnusedFields != 0 {
+		t.Errorf("fields = %d, unused = %d, want all 10 used", total, rotation.UnusedFields)
+	}
+	if wheat := fields["Wheat"]; wheat.Demand == 0 || wheat.Output < wheat.Demand {
+		t.Errorf("wheat = %+v, want the bakery's demand grown", wheat)
+	}
+	if len(rotation.UnmetDemand) != 0 {
+		t.Errorf("unmet demand = %v, want none", rotation.UnmetDemand)
+	}
+}
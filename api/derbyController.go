@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/derby"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type DerbyController struct {
+	repo     *GoodsRepository
+	profiles *ProfileRepository
+}
+
+func NewDerbyController(repo *GoodsRepository, profiles *ProfileRepository) *DerbyController {
+	return &DerbyController{
+		repo:     repo,
+		profiles: profiles,
+	}
+}
+
+func (a *DerbyController) Init(router *http.ServeMux) {
+	router.HandleFunc("POST /derby/tasks/evaluate", a.evaluateTasks)
+}
+
+func (a *DerbyController) evaluateTasks(w http.ResponseWriter, r *http.Request) {
+	var request models.DerbyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := models.PlayerState{Level: request.Level}
+	if request.ProfileID != "" {
+		id, err := parseProfileID(request.ProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		profile, err := a.profiles.GetProfileByID(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		state = playerStateFromProfile(*profile)
+	}
+	state.Inventory = request.Inventory
+	if err := validateInventory(a.repo, request.Inventory); err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	estimator := derby.NewTaskEstimator(availableGoods(a.repo, state), NewBOMExpander(a.repo.GetAllGoods()), state)
+
+	evaluations, err := estimator.EvaluateAll(request.Tasks, request.MinPointsPerHour)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(evaluations)
+}
@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDerbyControllerStatus(t *testing.T) {
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "tasks", method: http.MethodPost, path: "/derby/tasks/evaluate", body: `{"Level":5,"Tasks":[{"Type":"produce_good","Good":"Bread","Amount":3,"Points":320,"TimeLimitHours":8}]}`, status: http.StatusOK},
+		{name: "empty task", method: http.MethodPost, path: "/derby/tasks/evaluate", body: `{"Level":5,"Tasks":[{"Type":"produce_good","Good":"Bread","Amount":0,"Points":320}]}`, status: http.StatusBadRequest},
+		{name: "negative inventory", method: http.MethodPost, path: "/derby/tasks/evaluate", body: `{"Level":5,"Inventory":{"Wheat":-3}}`, status: http.StatusBadRequest},
+		{name: "unknown profile", method: http.MethodPost, path: "/derby/tasks/evaluate", body: `{"ProfileID":"6ba7b811-9dad-11d1-80b4-00c04fd430c8"}`, status: http.StatusNotFound},
+	})
+}
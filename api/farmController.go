@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type FarmController struct {
+	repo       *GoodsRepository
+	profiles   *ProfileRepository
+	strategies *StrategyRegistry
+}
+
+func NewFarmController(repo *GoodsRepository, profiles *ProfileRepository, strategies *StrategyRegistry) *FarmController {
+	return &FarmController{
+		repo:       repo,
+		profiles:   profiles,
+		strategies: strategies,
+	}
+}
+
+func (a *FarmController) Init(router *http.ServeMux) {
+	router.HandleFunc("POST /farm/fields/rotation", a.planCropRotation)
+	router.HandleFunc("POST /farm/animals/balance", a.balanceFeed)
+	router.HandleFunc("POST /farm/orchard", a.planOrchard)
+}
+
+func (a *FarmController) planCropRotation(w http.ResponseWriter, r *http.Request) {
+	var request models.FieldRotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := models.PlayerState{Level: request.Level}
+	fields := request.Fields
+	if request.ProfileID != "" {
+		id, err := parseProfileID(request.ProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		profile, err := a.profiles.GetProfileByID(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		state = playerStateFromProfile(*profile)
+		if fields == 0 {
+			fields = profile.Fields
+		}
+	}
+	state.Horizon = time.Duration(request.Hours) * time.Hour
+
+	planner := NewCropRotationPlanner(a.repo, a.strategies)
+
+	rotation, err := planner.Plan(r.Context(), state, fields, request.Strategy)
+	if err != nil {
+		http.Error(w, err.Error(), planningErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(rotation)
+}
+
+func (a *FarmController) balanceFeed(w http.ResponseWriter, r *http.Request) {
+	var request models.FeedBalanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	level, animals := request.Level, request.Animals
+	feedMills := models.DefaultSourceCount(level, models.SourceFeedMill)
+	if request.ProfileID != "" {
+		id, err := parseProfileID(request.ProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		profile, err := a.profiles.GetProfileByID(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		level = profile.Level
+		if animals == nil {
+			animals = profile.Animals
+		}
+		feedMills = playerStateFromProfile(*profile).Capacity[models.SourceFeedMill]
+	}
+	if request.FeedMills != nil {
+		feedMills = *request.FeedMills
+	}
+
+	balancer := NewFeedBalancer(a.repo)
+
+	balance, err := balancer.Balance(level, animals, feedMills, time.Duration(request.Hours)*time.Hour)
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(balance)
+}
+
+func (a *FarmController) planOrchard(w http.ResponseWriter, r *http.Request) {
+	var request models.OrchardRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := models.PlayerState{Level: request.Level}
+	owned := make(map[string]int)
+	if request.ProfileID != "" {
+		id, err := parseProfileID(request.ProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		profile, err := a.profiles.GetProfileByID(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		state = playerStateFromProfile(*profile)
+
+		plots := 0
+		for _, counts := range []map[string]int{profile.Trees, profile.Bushes} {
+			for source, count := range counts {
+				owned[source] = count
+				plots += count
+			}
+		}
+		if request.Plots == 0 {
+			request.Plots = plots
+		}
+	}
+	state.Horizon = time.Duration(request.Hours) * time.Hour
+
+	planner := NewOrchardPlanner(a.repo, a.strategies)
+
+	orchard, err := planner.Plan(r.Context(), state, request, owned)
+	if err != nil {
+		http.Error(w, err.Error(), planningErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(orchard)
+}
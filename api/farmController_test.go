@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestFarmControllerStatus(t *testing.T) {
+	unknownProfile := `"ProfileID":"6ba7b811-9dad-11d1-80b4-00c04fd430c8"`
+
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "rotation", method: http.MethodPost, path: "/farm/fields/rotation", body: `{"Level":5,"Fields":6}`, status: http.StatusOK},
+		{name: "rotation without fields", method: http.MethodPost, path: "/farm/fields/rotation", body: `{"Level":5}`, status: http.StatusBadRequest},
+		{name: "rotation unknown profile", method: http.MethodPost, path: "/farm/fields/rotation", body: `{` + unknownProfile + `}`, status: http.StatusNotFound},
+		{name: "balance", method: http.MethodPost, path: "/farm/animals/balance", body: `{"Level":5,"Animals":{"Chicken":3}}`, status: http.StatusOK},
+		{name: "balance without mills", method: http.MethodPost, path: "/farm/animals/balance", body: `{"Level":5,"Animals":{"Chicken":3},"FeedMills":0}`, status: http.StatusOK},
+		{name: "balance negative mills", method: http.MethodPost, path: "/farm/animals/balance", body: `{"Level":5,"FeedMills":-1}`, status: http.StatusBadRequest},
+		{name: "balance negative animals", method: http.MethodPost, path: "/farm/animals/balance", body: `{"Level":5,"Animals":{"Chicken":-3}}`, status: http.StatusBadRequest},
+		{name: "balance malformed", method: http.MethodPost, path: "/farm/animals/balance", body: `{"Level":`, status: http.StatusBadRequest},
+		{name: "orchard", method: http.MethodPost, path: "/farm/orchard", body: `{"Level":5,"Plots":2}`, status: http.StatusOK},
+		{name: "orchard negative plots", method: http.MethodPost, path: "/farm/orchard", body: `{"Level":5,"Plots":-2}`, status: http.StatusBadRequest},
+		{name: "orchard negative regrowth", method: http.MethodPost, path: "/farm/orchard", body: `{"Level":5,"Lifecycles":{"Apple tree":{"RegrowthHours":-1}}}`, status: http.StatusBadRequest},
+	})
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Sizes the Feed Mill and the fields behind it so that every animal can
+// produce without a break. Feed is valued like everywhere else, so the profit
+// of a pen matches the net value the strategies see for its good.
+type FeedBalancer struct {
+	repo      *GoodsRepository
+	goodsMap  map[uuid.UUID]models.HayDayGood
+	optimizer *Optimizer
+}
+
+func NewFeedBalancer(repo *GoodsRepository) *FeedBalancer {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	for _, good := range repo.GetAllGoods() {
+		goodsMap[good.ID] = good
+	}
+
+	return &FeedBalancer{
+		repo:      repo,
+		goodsMap:  goodsMap,
+		optimizer: NewOptimizer(repo.GetAllGoods(), MetricNetProfit),
+	}
+}
+
+func (b *FeedBalancer) Balance(level int, animals map[string]int, feedMills int, horizon time.Duration) (models.FeedBalance, error) {
+	if feedMills < 0 {
+		return models.FeedBalance{}, base.ErrInvalidCapacity
+	}
+	for _, count := range animals {
+		if count < 0 {
+			return models.FeedBalance{}, base.ErrInvalidCapacity
+		}
+	}
+
+	if horizon <= 0 {
+		horizon = defaultPlanningHorizon
+	}
+
+	balance := models.FeedBalance{
+		Horizon:          horizon,
+		Pens:             []models.AnimalPen{},
+		Feed:             []models.FeedSupply{},
+		Crops:            []models.CropInput{},
+		FeedMills:        feedMills,
+		FeedMillCoverage: 1,
+	}
+
+	available := groupGoodsBySource(b.repo.GetGoodsByLevel(level))
+	feedUnits := make(map[string]int)
+	feedGoods := make(map[string]models.HayDayGood)
+
+	for _, animal := range sortedAnimals(animals) {
+		good, found := animalGood(available[animal])
+		if !found {
+			continue
+		}
+
+		pen := models.AnimalPen{
+			Animal: animal,
+			Count:  animals[animal],
+			Good:   good.Name,
+			Output: animals[animal] * int(horizon/good.ProductionTime),
+		}
+		pen.GrossValue = pen.Output * good.MaxPrice
+
+		for _, ingredient := range good.Ingredients {
+			feed, exists := b.goodsMap[ingredient.ProductID]
+			if !exists || feed.Source != models.SourceFeedMill {
+				continue
+			}
+			pen.Feed = feed.Name
+			pen.FeedNeeded += pen.Output * ingredient.Amount
+
+			feedUnits[feed.Name] += pen.Output * ingredient.Amount
+			feedGoods[feed.Name] = feed
+		}
+
+		unitCost := b.optimizer.ingredientValue(good, make(map[uuid.UUID]bool))
+
+		pen.FeedCost = unitCost * float64(pen.Output)
+		pen.Profit = float64(pen.GrossValue) - pen.FeedCost
+		pen.UnitProfit = float64(good.MaxPrice) - unitCost
+		pen.ProfitPerHour = pen.Profit / horizon.Hours()
+
+		balance.Pens = append(balance.Pens, pen)
+		balance.Profit += pen.Profit
+	}
+	balance.ProfitPerHour = balance.Profit / horizon.Hours()
+
+	var millTime time.Duration
+	cropAmounts := make(map[string]int)
+	for name, units := range feedUnits {
+		feed := feedGoods[name]
+		supply := models.FeedSupply{
+			Feed:    name,
+			Units:   units,
+			Batches: models.Batches(feed.Source, units),
+		}
+		supply.MachineTime = feed.ProductionTime * time.Duration(supply.Batches)
+		millTime += supply.MachineTime
+
+		for _, ingredient := range feed.Ingredients {
+			if crop, exists := b.goodsMap[ingredient.ProductID]; exists {
+				cropAmounts[crop.Name] += supply.Batches * ingredient.Amount
+			}
+		}
+
+		balance.Feed = append(balance.Feed, supply)
+	}
+	sort.Slice(balance.Feed, func(i, j int) bool {
+		return balance.Feed[i].Feed < balance.Feed[j].Feed
+	})
+
+	balance.FeedMillsNeeded = int((millTime + horizon - 1) / horizon)
+	if feedMills < balance.FeedMillsNeeded {
+		balance.FeedMillCoverage = float64(time.Duration(feedMills)*horizon) / float64(millTime)
+	}
+
+	for name, amount := range cropAmounts {
+		input := models.CropInput{
+			Crop:   name,
+			Amount: amount,
+		}
+		if crop, err := b.repo.GetGoodByName(name); err == nil {
+			if perField := cropOutputPerField(*crop, horizon); perField > 0 {
+				input.Fields = (amount + perField - 1) / perField
+			}
+		}
+		balance.FieldsNeeded += input.Fields
+		balance.Crops = append(balance.Crops, input)
+	}
+	sort.Slice(balance.Crops, func(i, j int) bool {
+		return balance.Crops[i].Crop < balance.Crops[j].Crop
+	})
+
+	return balance, nil
+}
+
+// Animals with at least one head, in a stable order
+func sortedAnimals(animals map[string]int) []string {
+	var names []string
+	for name, count := range animals {
+		if models.AnimalSources[name] && count > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Every animal gives a single good, the most valuable one wins should the
+// data ever list more
+func animalGood(goods models.HayDayGoodList) (models.HayDayGood, bool) {
+	var best models.HayDayGood
+	found := false
+	for _, good := range goods {
+		if good.ProductionTime <= 0 {
+			continue
+		}
+		if !found || good.MaxPrice > best.MaxPrice {
+			best, found = good, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+)
+
+func TestFeedBalancerCoverage(t *testing.T) {
+	// Three chickens lay nine eggs an hour, three batches of feed keep the
+	// Feed Mill busy for a quarter of it
+	tests := []struct {
+		name      string
+		animals   map[string]int
+		feedMills int
+		coverage  float64
+		want      error
+	}{
+		{name: "enough mills", animals: map[string]int{"Chicken": 3}, feedMills: 1, coverage: 1},
+		{name: "no mill", animals: map[string]int{"Chicken": 3}, feedMills: 0, coverage: 0},
+		{name: "no feed needed", animals: map[string]int{}, feedMills: 0, coverage: 1},
+		{name: "negative mills", animals: map[string]int{"Chicken": 3}, feedMills: -1, want: base.ErrInvalidCapacity},
+		{name: "negative animals", animals: map[string]int{"Chicken": -3}, feedMills: 1, want: base.ErrInvalidCapacity},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			balance, err := NewFeedBalancer(testRepository()).Balance(1, test.animals, test.feedMills, time.Hour)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("err = %v, want %v", err, test.want)
+			}
+			if err != nil {
+				return
+			}
+
+			if balance.FeedMillCoverage != test.coverage {
+				t.Errorf("coverage = %v, want %v", balance.FeedMillCoverage, test.coverage)
+			}
+		})
+	}
+}
+
+func TestFeedBalancerSizesMillAndFields(t *testing.T) {
+	balance, err := NewFeedBalancer(testRepository()).Balance(2, map[string]int{"Chicken": 3}, 1, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(balance.Pens) != 1 || balance.Pens[0].Output != 9 || balance.Pens[0].FeedNeeded != 9 {
+		t.Fatalf("pens = %+v, want nine eggs from nine feed", balance.Pens)
+	}
+	if len(balance.Feed) != 1 || balance.Feed[0].Batches != 3 || balance.Feed[0].MachineTime != 15*time.Minute {
+		t.Errorf("feed = %+v, want three batches in a quarter hour", balance.Feed)
+	}
+	if balance.FeedMillsNeeded != 1 {
+		t.Errorf("feed mills needed = %d, want 1", balance.FeedMillsNeeded)
+	}
+}
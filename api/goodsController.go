@@ -4,23 +4,46 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+	"github.com/noTirT/hayday-optimizer/simulation"
+)
+
+const (
+	defaultStrategy = "greedy"
+	// Enough to follow the first hours of a day on a large farm
+	defaultTimelineEvents = 2000
 )
 
 type GoodsController struct {
-	repo *GoodsRepository
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
 }
 
-func NewGoodsController(repo *GoodsRepository) *GoodsController {
+func NewGoodsController(repo *GoodsRepository, strategies *StrategyRegistry) *GoodsController {
 	return &GoodsController{
-		repo: repo,
+		repo:       repo,
+		strategies: strategies,
 	}
 }
 
 func (a *GoodsController) Init(router *http.ServeMux) {
 	router.HandleFunc("GET /goods", a.getGoods)
 	router.HandleFunc("GET /goods/{name}", a.getGoodByName)
+	// A literal /goods/{name}/bom would conflict with /goods/level/{level}
+	router.HandleFunc("GET /goods/{name}/{view}", a.getGoodView)
 	router.HandleFunc("GET /goods/level/{level}", a.getGoodsByLevel)
+	router.HandleFunc("GET /goods/strategies", a.getStrategyNames)
 	router.HandleFunc("GET /goods/strategy/{level}", a.getMostProfitableGoods)
+	router.HandleFunc("POST /goods/strategy", a.planWithInventory)
+	router.HandleFunc("GET /goods/session/{level}", a.planSession)
+	router.HandleFunc("GET /goods/strategy/{level}/simulation", a.simulateStrategy)
+	router.HandleFunc("GET /goods/strategy/{level}/schedule", a.scheduleStrategy)
+	router.HandleFunc("GET /goods/strategy/{level}/pareto", a.getParetoFrontier)
+	router.HandleFunc("GET /goods/strategy/{level}/sensitivity", a.analyzeSensitivity)
+	router.HandleFunc("POST /goods/roadmap", a.planRoadmap)
 }
 
 func (a *GoodsController) getGoods(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +58,42 @@ func (a *GoodsController) getGoodByName(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	json.NewEncoder(w).Encode(good)
+	expander := NewBOMExpander(a.repo.GetAllGoods())
+
+	json.NewEncoder(w).Encode(models.GoodDetails{
+		HayDayGood: *good,
+		LeadTime:   expander.LeadTime(*good),
+	})
+}
+
+func (a *GoodsController) getGoodView(w http.ResponseWriter, r *http.Request) {
+	switch r.PathValue("view") {
+	case "bom":
+		a.getBillOfMaterials(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (a *GoodsController) getBillOfMaterials(w http.ResponseWriter, r *http.Request) {
+	good, err := a.repo.GetGoodByName(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	quantity := 1
+	if rawQuantity := r.URL.Query().Get("quantity"); rawQuantity != "" {
+		quantity, err = strconv.Atoi(rawQuantity)
+		if err != nil || quantity < 1 {
+			http.Error(w, base.ErrInvalidQuantity.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	expander := NewBOMExpander(a.repo.GetAllGoods())
+
+	json.NewEncoder(w).Encode(expander.Expand(*good, quantity))
 }
 
 func (a *GoodsController) getGoodsByLevel(w http.ResponseWriter, r *http.Request) {
@@ -49,17 +107,217 @@ func (a *GoodsController) getGoodsByLevel(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(goods)
 }
 
+func (a *GoodsController) getStrategyNames(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(a.strategies.Names())
+}
+
 func (a *GoodsController) getMostProfitableGoods(w http.ResponseWriter, r *http.Request) {
-	level := r.PathValue("level")
+	state, err := parsePlayerState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	parsedLevel, err := strconv.Atoi(level)
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := runStrategy(r.Context(), a.strategies, strategyName, state)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(plan)
+}
+
+func (a *GoodsController) planWithInventory(w http.ResponseWriter, r *http.Request) {
+	var request models.StrategyRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := validateInventory(a.repo, request.Inventory); err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+	if request.VisitMinutes < 0 {
+		http.Error(w, base.ErrInvalidTimeString.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.BarnCapacity < 0 || request.SiloCapacity < 0 {
+		http.Error(w, base.ErrInvalidCapacity.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := models.PlayerState{
+		Level:         request.Level,
+		XPToTarget:    request.XPToTarget,
+		TargetLevel:   request.TargetLevel,
+		XPToNextLevel: request.XPToNextLevel,
+		Horizon:       time.Duration(request.Hours) * time.Hour,
+		VisitInterval: time.Duration(request.VisitMinutes) * time.Minute,
+		Inventory:     request.Inventory,
+		BarnCapacity:  request.BarnCapacity,
+		SiloCapacity:  request.SiloCapacity,
+		Explain:       request.Explain,
+	}
+
+	plan, err := runStrategy(r.Context(), a.strategies, request.Strategy, state)
 	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
 		return
 	}
 
-	optimizer := NewOptimizer(a.repo.GetAllGoods())
+	NewInventoryPlanner(a.repo.GetAllGoods()).Apply(&plan, state)
+
+	json.NewEncoder(w).Encode(plan)
+}
+
+func (a *GoodsController) planSession(w http.ResponseWriter, r *http.Request) {
+	state, err := parsePlayerState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session, offline, err := parseSessionWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	planner := NewSessionPlanner(a.repo.GetAllGoods())
+
+	json.NewEncoder(w).Encode(planner.Plan(availableGoods(a.repo, state), state, session, offline))
+}
+
+func (a *GoodsController) simulateStrategy(w http.ResponseWriter, r *http.Request) {
+	state, err := parsePlayerState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	availableGoods := a.repo.GetGoodsByLevel(parsedLevel)
+	timelineLimit, err := parseTimelineLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := runStrategy(r.Context(), a.strategies, strategyName, state)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	setup := simulationSetup(a.repo, state)
+	setup.TimelineLimit = timelineLimit
+	simulator := simulation.NewFarmSimulator(a.repo.GetAllGoods(), setup)
+
+	json.NewEncoder(w).Encode(simulator.Run(plan.Goods))
+}
+
+func (a *GoodsController) scheduleStrategy(w http.ResponseWriter, r *http.Request) {
+	state, err := parsePlayerState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := runStrategy(r.Context(), a.strategies, strategyName, state)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	scheduler := NewProductionScheduler(a.repo.GetAllGoods())
+
+	json.NewEncoder(w).Encode(scheduler.Schedule(plan.Goods, state, time.Now()))
+}
+
+func (a *GoodsController) getParetoFrontier(w http.ResponseWriter, r *http.Request) {
+	state, err := parsePlayerState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	weights, err := parseObjectiveWeights(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	planner := NewParetoPlanner(a.repo.GetAllGoods())
+
+	json.NewEncoder(w).Encode(planner.Plan(availableGoods(a.repo, state), state.Level, weights))
+}
+
+func (a *GoodsController) analyzeSensitivity(w http.ResponseWriter, r *http.Request) {
+	state, err := parsePlayerState(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	percent, err := parseOptionalFloat(r, "percent")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if percent == 0 {
+		percent = defaultSensitivityPercent
+	}
+	if percent < 0 || percent >= 100 {
+		http.Error(w, base.ErrInvalidPercentage.Error(), http.StatusBadRequest)
+		return
+	}
+
+	analyzer := NewSensitivityAnalyzer(a.repo, a.strategies)
+
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := analyzer.Analyze(r.Context(), strategyName, state, percent)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
+
+func (a *GoodsController) planRoadmap(w http.ResponseWriter, r *http.Request) {
+	var request models.RoadmapRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	roadmap, err := NewRoadmapPlanner(a.repo, a.strategies).Plan(r.Context(), request)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
 
-	json.NewEncoder(w).Encode(optimizer.GetOptimizedPlan(availableGoods))
+	json.NewEncoder(w).Encode(roadmap)
 }
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPlanWithInventoryValidation(t *testing.T) {
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "valid", method: http.MethodPost, path: "/goods/strategy", body: `{"Level":5,"Inventory":{"Wheat":10}}`, status: http.StatusOK},
+		{name: "negative count", method: http.MethodPost, path: "/goods/strategy", body: `{"Level":5,"Inventory":{"Wheat":-100}}`, status: http.StatusBadRequest},
+		{name: "unknown good", method: http.MethodPost, path: "/goods/strategy", body: `{"Level":5,"Inventory":{"Gold":1}}`, status: http.StatusBadRequest},
+		{name: "negative barn", method: http.MethodPost, path: "/goods/strategy", body: `{"Level":5,"BarnCapacity":-1}`, status: http.StatusBadRequest},
+		{name: "negative silo", method: http.MethodPost, path: "/goods/strategy", body: `{"Level":5,"SiloCapacity":-1}`, status: http.StatusBadRequest},
+		{name: "malformed", method: http.MethodPost, path: "/goods/strategy", body: `{"Level":`, status: http.StatusBadRequest},
+	})
+}
+
+func TestGoodsControllerStatus(t *testing.T) {
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "good", method: http.MethodGet, path: "/goods/Bread", status: http.StatusOK},
+		{name: "strategy", method: http.MethodGet, path: "/goods/strategy/5", status: http.StatusOK},
+		{name: "level not a number", method: http.MethodGet, path: "/goods/strategy/five", status: http.StatusBadRequest},
+		{name: "unknown strategy", method: http.MethodGet, path: "/goods/strategy/5?strategy=nope", status: http.StatusBadRequest},
+		{name: "negative visit", method: http.MethodGet, path: "/goods/strategy/5?visit=-5m", status: http.StatusBadRequest},
+		{name: "schedule", method: http.MethodGet, path: "/goods/strategy/5/schedule", status: http.StatusOK},
+		{name: "simulation", method: http.MethodGet, path: "/goods/strategy/5/simulation?timeline=all", status: http.StatusOK},
+		{name: "simulation timeline not a number", method: http.MethodGet, path: "/goods/strategy/5/simulation?timeline=some", status: http.StatusBadRequest},
+		{name: "session too long", method: http.MethodGet, path: "/goods/session/5?session=40h&offline=10h", status: http.StatusBadRequest},
+	})
+}
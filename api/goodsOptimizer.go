@@ -1,17 +1,70 @@
 package api
 
 import (
+	"math"
+	"sort"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/base"
 	"github.com/noTirT/hayday-optimizer/models"
 )
 
+type RankingMetric string
+
+const (
+	// Rank goods by their final sale price
+	MetricMaxPrice RankingMetric = "max_price"
+	// Rank goods by coins earned per hour of machine time
+	MetricCoinsPerHour RankingMetric = "coins_per_hour"
+	// Rank goods by sale price minus the value of the raw materials they consume
+	MetricNetProfit RankingMetric = "net_profit"
+	// Rank goods by experience the whole ingredient chain earns per hour of
+	// its busiest source
+	MetricXPPerHour RankingMetric = "xp_per_hour"
+	// Rank goods by experience the whole ingredient chain earns per base
+	// ingredient it consumes
+	MetricXPPerIngredient RankingMetric = "xp_per_ingredient"
+	// Rank goods by coins per hour of end-to-end lead time including ingredients
+	MetricCoinsPerLeadHour RankingMetric = "coins_per_lead_hour"
+	// Rank goods by a weighted blend of net coins per hour, experience per hour
+	// and short production time
+	MetricWeighted RankingMetric = "weighted"
+)
+
+// Strategy that ranks by each metric, so the metric parameter from before
+// strategies existed keeps selecting the same plan
+var metricStrategies = map[RankingMetric]string{
+	MetricMaxPrice:         "legacy-greedy",
+	MetricNetProfit:        "greedy",
+	MetricCoinsPerHour:     "coins_per_hour",
+	MetricCoinsPerLeadHour: "coins_per_lead_hour",
+	MetricXPPerHour:        "xp",
+	MetricXPPerIngredient:  "xp_per_ingredient",
+}
+
+func ParseRankingMetric(value string) (RankingMetric, error) {
+	metric := RankingMetric(value)
+	if _, exists := metricStrategies[metric]; !exists {
+		return "", base.ErrUnknownRankingMetric
+	}
+	return metric, nil
+}
+
 type Optimizer struct {
 	allGoods                   models.HayDayGoodList
 	goodsMap                   map[uuid.UUID]models.HayDayGood
+	metric                     RankingMetric
+	rawMaterialValues          map[uuid.UUID]float64
+	lifecycles                 map[string]models.TreeLifecycle
+	expander                   *BOMExpander
+	weights                    models.ObjectiveWeights
+	objectiveScales            models.ObjectiveWeights
+	decisions                  []models.PlanDecision
 	currentMostProfitableGoods models.HayDayGoodList
 }
 
-func NewOptimizer(allGoods models.HayDayGoodList) *Optimizer {
+func NewOptimizer(allGoods models.HayDayGoodList, metric RankingMetric) *Optimizer {
 	goodsMap := make(map[uuid.UUID]models.HayDayGood)
 	for _, good := range allGoods {
 		goodsMap[good.ID] = good
@@ -20,30 +73,63 @@ func NewOptimizer(allGoods models.HayDayGoodList) *Optimizer {
 	return &Optimizer{
 		allGoods: allGoods,
 		goodsMap: goodsMap,
+		metric:   metric,
+		// Cache for the recursive ingredient valuation
+		rawMaterialValues: make(map[uuid.UUID]float64),
+		expander:          NewBOMExpander(allGoods),
+		decisions:         []models.PlanDecision{},
 		// For internal state management
 		currentMostProfitableGoods: models.HayDayGoodList{},
 	}
 }
 
+// Value trees and bushes with the given lifecycles instead of the defaults
+func (o *Optimizer) withLifecycles(lifecycles map[string]models.TreeLifecycle) *Optimizer {
+	o.lifecycles = lifecycles
+	return o
+}
+
+// Optimizer for MetricWeighted. Every objective is scaled by its largest
+// value over all goods so that the weights compare like with like.
+func NewWeightedOptimizer(allGoods models.HayDayGoodList, weights models.ObjectiveWeights) *Optimizer {
+	optimizer := NewOptimizer(allGoods, MetricWeighted)
+	optimizer.weights = weights
+
+	for _, good := range allGoods {
+		optimizer.objectiveScales.Coins = max(optimizer.objectiveScales.Coins, optimizer.netCoinsPerHour(good))
+		optimizer.objectiveScales.XP = max(optimizer.objectiveScales.XP, xpPerHour(good))
+		optimizer.objectiveScales.Time = max(optimizer.objectiveScales.Time, good.ProductionTime.Hours())
+	}
+
+	return optimizer
+}
+
 // Main process of optimization
-func (o *Optimizer) GetOptimizedPlan(availableGoods models.HayDayGoodList) models.HayDayGoodList {
+func (o *Optimizer) GetOptimizedPlan(availableGoods models.HayDayGoodList) models.PlannedGoodList {
 	o.selectMostProfitablePerSource(availableGoods)
 
 	o.filterOutBaseProductsInIngredientChain()
 
 	o.removeIngredientsOfHigherPricedProducts()
 
-	o.filterGoods(func(good models.HayDayGood) bool {
-		return good.Source != "Feed Mill"
-	})
-
 	o.removeProductsWithSourceConflicts()
 
-	o.filterGoods(func(good models.HayDayGood) bool {
-		return good.MaxPrice > 0
+	o.filterGoods(models.RuleNotProfitable, func(good models.HayDayGood) (bool, models.PlanDecision) {
+		return o.score(good) > 0, models.PlanDecision{}
 	})
 
-	return o.currentMostProfitableGoods
+	plan := make(models.PlannedGoodList, 0, len(o.currentMostProfitableGoods))
+	for _, good := range o.currentMostProfitableGoods {
+		plan = append(plan, models.PlannedGood{
+			HayDayGood:      good,
+			GrossValue:      good.MaxPrice,
+			NetValue:        o.netValue(good),
+			XPPerHour:       xpPerHour(good),
+			XPPerIngredient: o.chainXPPerIngredient(good),
+		})
+	}
+
+	return plan
 }
 
 // Return all the most profitable goods but only one per different source
@@ -57,40 +143,52 @@ func (o *Optimizer) selectMostProfitablePerSource(availableGoods models.HayDayGo
 			continue
 		}
 		mostProfitableGood := goods[0]
-		maxProfit := mostProfitableGood.MaxPrice
+		maxProfit := o.score(mostProfitableGood)
 
 		for _, good := range goods[1:] {
-			profit := good.MaxPrice
+			profit := o.score(good)
 			if profit > maxProfit {
 				mostProfitableGood = good
 				maxProfit = profit
 			}
 		}
 		mostProfitable = append(mostProfitable, mostProfitableGood)
+
+		for _, good := range goods {
+			if good.ID != mostProfitableGood.ID {
+				o.decide(good, models.PlanDecision{
+					Rule:     models.RuleNotBestOfSource,
+					CausedBy: mostProfitableGood.Name,
+					Source:   good.Source,
+				})
+			}
+		}
 	}
 
-	sortGoodsByPriceDescending(mostProfitable)
+	o.sortGoodsByScoreDescending(mostProfitable)
 
 	o.currentMostProfitableGoods = mostProfitable
 }
 
 // Remove all base products from the List that are also ingredients of non-base products
 func (o *Optimizer) filterOutBaseProductsInIngredientChain() {
-	baseProductIDsToRemove := make(map[uuid.UUID]bool)
+	// Maps each marked base product to the product whose chain contains it
+	baseProductIDsToRemove := make(map[uuid.UUID]string)
 
 	for _, profitable := range o.currentMostProfitableGoods {
 		if !isBaseProduct(profitable) {
-			o.findBaseProductsInChain(profitable, baseProductIDsToRemove)
+			o.findBaseProductsInChain(profitable, profitable.Name, baseProductIDsToRemove)
 		}
 	}
 
-	o.filterGoods(func(good models.HayDayGood) bool {
-		return !isBaseProduct(good) || !baseProductIDsToRemove[good.ID]
+	o.filterGoods(models.RuleBaseProductInChain, func(good models.HayDayGood) (bool, models.PlanDecision) {
+		causedBy, marked := baseProductIDsToRemove[good.ID]
+		return !isBaseProduct(good) || !marked, models.PlanDecision{CausedBy: causedBy}
 	})
 }
 
 // Recursively find nested ingredients that are base products
-func (o *Optimizer) findBaseProductsInChain(good models.HayDayGood, baseProductIDs map[uuid.UUID]bool) {
+func (o *Optimizer) findBaseProductsInChain(good models.HayDayGood, root string, baseProductIDs map[uuid.UUID]string) {
 	// Check direct ingredients
 	for _, ingredient := range good.Ingredients {
 		ingredientGood, exists := o.goodsMap[ingredient.ProductID]
@@ -100,10 +198,12 @@ func (o *Optimizer) findBaseProductsInChain(good models.HayDayGood, baseProductI
 
 		// If this ingredient is a base product, mark it
 		if isBaseProduct(ingredientGood) {
-			baseProductIDs[ingredientGood.ID] = true
+			if _, marked := baseProductIDs[ingredientGood.ID]; !marked {
+				baseProductIDs[ingredientGood.ID] = root
+			}
 		} else {
 			// If it's not a base product, check its ingredients recursively
-			o.findBaseProductsInChain(ingredientGood, baseProductIDs)
+			o.findBaseProductsInChain(ingredientGood, root, baseProductIDs)
 		}
 	}
 }
@@ -111,52 +211,57 @@ func (o *Optimizer) findBaseProductsInChain(good models.HayDayGood, baseProductI
 // Remove all products that are ingredients of products with higher price
 func (o *Optimizer) removeIngredientsOfHigherPricedProducts() {
 	// Track which products are ingredients of higher-priced products
-	ingredientsToRemove := make(map[uuid.UUID]bool)
+	// together with the product whose chain they belong to
+	ingredientsToRemove := make(map[uuid.UUID]string)
 
-	sortGoodsByPriceDescending(o.currentMostProfitableGoods)
+	o.sortGoodsByScoreDescending(o.currentMostProfitableGoods)
 
 	// For each profitable good, check if any of the other profitable goods
 	// are in its ingredient chain
 	for _, profitable := range o.currentMostProfitableGoods {
-		o.markIngredientsInChain(profitable, ingredientsToRemove)
+		o.markIngredientsInChain(profitable, profitable.Name, ingredientsToRemove)
 	}
 
 	// Filter out the products that are ingredients of higher-priced products
-	o.filterGoods(func(good models.HayDayGood) bool {
-		return !ingredientsToRemove[good.ID]
+	o.filterGoods(models.RuleIngredientOfHigherRanked, func(good models.HayDayGood) (bool, models.PlanDecision) {
+		causedBy, marked := ingredientsToRemove[good.ID]
+		return !marked, models.PlanDecision{CausedBy: causedBy}
 	})
 }
 
-func (o *Optimizer) markIngredientsInChain(good models.HayDayGood, ingredientsToRemove map[uuid.UUID]bool) {
+func (o *Optimizer) markIngredientsInChain(good models.HayDayGood, root string, ingredientsToRemove map[uuid.UUID]string) {
 	// Check direct ingredients
 	for _, ingredient := range good.Ingredients {
 		// If this ingredient is in our profitable goods, mark it for removal
 		if _, exists := o.goodsMap[ingredient.ProductID]; exists {
-			ingredientsToRemove[ingredient.ProductID] = true
+			if _, marked := ingredientsToRemove[ingredient.ProductID]; !marked {
+				ingredientsToRemove[ingredient.ProductID] = root
+			}
 		}
 
 		// Continue checking the ingredient chain
 		ingredientGood, exists := o.goodsMap[ingredient.ProductID]
 		if exists && len(ingredientGood.Ingredients) > 0 {
-			o.markIngredientsInChain(ingredientGood, ingredientsToRemove)
+			o.markIngredientsInChain(ingredientGood, root, ingredientsToRemove)
 		}
 	}
 }
 
 // Remove Products where their source is also the source of the ingredients of higher profitable products
 func (o *Optimizer) removeProductsWithSourceConflicts() {
-	sortGoodsByPriceDescending(o.currentMostProfitableGoods)
+	o.sortGoodsByScoreDescending(o.currentMostProfitableGoods)
 
 	// Keep track of sources required by ingredients of higher-priced products
-	requiredSources := make(map[string]bool)
+	// and the first product that needed them
+	requiredSources := make(map[string]string)
 
-	// Products to remove due to source conflicts
-	productsToRemove := make(map[uuid.UUID]bool)
+	// Products to remove due to source conflicts, with the conflicting product
+	productsToRemove := make(map[uuid.UUID]string)
 
 	// Start with the highest priced product
 	for i, highPricedProduct := range o.currentMostProfitableGoods {
 		// Skip products already marked for removal
-		if productsToRemove[highPricedProduct.ID] {
+		if _, marked := productsToRemove[highPricedProduct.ID]; marked {
 			continue
 		}
 
@@ -165,7 +270,9 @@ func (o *Optimizer) removeProductsWithSourceConflicts() {
 
 		// Add these sources to our required sources
 		for source := range ingredientSources {
-			requiredSources[source] = true
+			if _, required := requiredSources[source]; !required {
+				requiredSources[source] = highPricedProduct.Name
+			}
 		}
 
 		// Check lower-priced products
@@ -174,15 +281,18 @@ func (o *Optimizer) removeProductsWithSourceConflicts() {
 
 			// If this product's source is needed by a higher-priced product's ingredients,
 			// mark it for removal
-			if requiredSources[lowerPricedProduct.Source] {
-				productsToRemove[lowerPricedProduct.ID] = true
+			if requiredBy, required := requiredSources[lowerPricedProduct.Source]; required {
+				if _, marked := productsToRemove[lowerPricedProduct.ID]; !marked {
+					productsToRemove[lowerPricedProduct.ID] = requiredBy
+				}
 			}
 		}
 	}
 
 	// Filter out products with source conflicts
-	o.filterGoods(func(good models.HayDayGood) bool {
-		return !productsToRemove[good.ID]
+	o.filterGoods(models.RuleSourceConflict, func(good models.HayDayGood) (bool, models.PlanDecision) {
+		causedBy, marked := productsToRemove[good.ID]
+		return !marked, models.PlanDecision{CausedBy: causedBy, Source: good.Source}
 	})
 }
 
@@ -217,12 +327,155 @@ func (o *Optimizer) getIngredientSources(good models.HayDayGood, visited map[uui
 	return sources
 }
 
-func (o *Optimizer) filterGoods(keepFn func(good models.HayDayGood) bool) {
+// Keep the goods keepFn accepts and log every removal under the given rule
+func (o *Optimizer) filterGoods(rule models.PlanRule, keepFn func(good models.HayDayGood) (bool, models.PlanDecision)) {
 	var result models.HayDayGoodList
 	for _, good := range o.currentMostProfitableGoods {
-		if keepFn(good) {
+		keep, decision := keepFn(good)
+		if keep {
 			result = append(result, good)
+			continue
 		}
+		decision.Rule = rule
+		o.decide(good, decision)
 	}
 	o.currentMostProfitableGoods = result
 }
+
+func (o *Optimizer) decide(good models.HayDayGood, decision models.PlanDecision) {
+	decision.Good = good.Name
+	o.decisions = append(o.decisions, decision)
+}
+
+// Why each good was dropped during the last GetOptimizedPlan
+func (o *Optimizer) Decisions() []models.PlanDecision {
+	return o.decisions
+}
+
+// Value of a good under the selected ranking metric, higher is better
+func (o *Optimizer) score(good models.HayDayGood) float64 {
+	switch o.metric {
+	case MetricCoinsPerHour:
+		return coinsPerHour(good)
+	case MetricNetProfit:
+		return float64(o.netValue(good))
+	case MetricXPPerHour:
+		return o.chainXPPerHour(good)
+	case MetricXPPerIngredient:
+		return o.chainXPPerIngredient(good)
+	case MetricCoinsPerLeadHour:
+		leadTime := o.expander.LeadTime(good)
+		if leadTime <= 0 {
+			return 0
+		}
+		return float64(good.MaxPrice) / leadTime.Hours()
+	case MetricWeighted:
+		return o.weightedScore(good)
+	default:
+		return float64(good.MaxPrice)
+	}
+}
+
+// Goods without a production time cannot run in a mix and score 0
+func (o *Optimizer) weightedScore(good models.HayDayGood) float64 {
+	if good.ProductionTime <= 0 {
+		return 0
+	}
+
+	score := 0.0
+	if o.objectiveScales.Coins > 0 {
+		score += o.weights.Coins * o.netCoinsPerHour(good) / o.objectiveScales.Coins
+	}
+	if o.objectiveScales.XP > 0 {
+		score += o.weights.XP * xpPerHour(good) / o.objectiveScales.XP
+	}
+	if o.objectiveScales.Time > 0 {
+		// Shorter is better, so the fastest good earns the full time weight
+		score += o.weights.Time * (1 - good.ProductionTime.Hours()/o.objectiveScales.Time)
+	}
+	return score
+}
+
+func (o *Optimizer) sortGoodsByScoreDescending(goods models.HayDayGoodList) {
+	sort.SliceStable(goods, func(i, j int) bool {
+		return o.score(goods[i]) > o.score(goods[j])
+	})
+}
+
+// Experience of a batch and everything made for it, per hour of the source
+// that is busiest making it. Like netValue it looks at the whole chain.
+func (o *Optimizer) chainXPPerHour(good models.HayDayGood) float64 {
+	bom := o.expander.Expand(good, models.BatchYield(good.Source))
+
+	var busiest time.Duration
+	for _, machineTime := range bom.MachineTime {
+		busiest = max(busiest, machineTime)
+	}
+	if busiest <= 0 {
+		return 0
+	}
+	return float64(bom.TotalXP) / busiest.Hours()
+}
+
+// Experience of a batch and everything made for it, per unit of base product
+// the chain consumes. Base products consume nothing, so all of their XP counts.
+func (o *Optimizer) chainXPPerIngredient(good models.HayDayGood) float64 {
+	bom := o.expander.Expand(good, models.BatchYield(good.Source))
+
+	consumed := 0
+	for _, item := range bom.Items {
+		if item.IsBaseProduct {
+			consumed += item.Amount
+		}
+	}
+	return float64(bom.TotalXP) / float64(max(consumed, 1))
+}
+
+// Sale price minus the value of everything consumed along the ingredient chain
+// and, for trees and bushes, the share of replanting every harvest carries
+func (o *Optimizer) netValue(good models.HayDayGood) int {
+	cost := o.ingredientValue(good, make(map[uuid.UUID]bool)) + replantCost(o.lifecycles, good)
+	return good.MaxPrice - int(math.Round(cost))
+}
+
+// A run of the source can hand out a whole batch of the good
+func (o *Optimizer) netCoinsPerHour(good models.HayDayGood) float64 {
+	if good.ProductionTime <= 0 {
+		return 0
+	}
+	return float64(o.netValue(good)*models.BatchYield(good.Source)) / good.ProductionTime.Hours()
+}
+
+// Summed sale value of the base products needed to make one unit of the good.
+// One set of ingredients makes a whole batch, each unit carries its share.
+func (o *Optimizer) ingredientValue(good models.HayDayGood, visited map[uuid.UUID]bool) float64 {
+	// Prevent infinite recursion with cycles
+	if visited[good.ID] {
+		return 0
+	}
+	visited[good.ID] = true
+	defer delete(visited, good.ID)
+
+	value := 0.0
+	for _, ingredient := range good.Ingredients {
+		ingredientGood, exists := o.goodsMap[ingredient.ProductID]
+		if !exists {
+			continue
+		}
+		value += float64(ingredient.Amount) * o.rawMaterialValue(ingredientGood, visited)
+	}
+	return value / float64(models.BatchYield(good.Source))
+}
+
+func (o *Optimizer) rawMaterialValue(good models.HayDayGood, visited map[uuid.UUID]bool) float64 {
+	if isBaseProduct(good) {
+		return float64(good.MaxPrice)
+	}
+	if value, cached := o.rawMaterialValues[good.ID]; cached {
+		return value
+	}
+
+	value := o.ingredientValue(good, visited)
+	o.rawMaterialValues[good.ID] = value
+	return value
+}
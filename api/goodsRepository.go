@@ -21,6 +21,13 @@ func NewGoodsRepository(fileManager *base.FileManager[models.HayDayGoodList]) *G
 	}
 }
 
+// Repository over goods that are already in memory
+func newGoodsRepositoryFromList(goods models.HayDayGoodList) *GoodsRepository {
+	return &GoodsRepository{
+		goods: goods,
+	}
+}
+
 func (repo *GoodsRepository) GetAllGoods() models.HayDayGoodList {
 	return repo.goods
 }
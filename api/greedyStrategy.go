@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Runs the filter pipeline of the Optimizer with a fixed ranking metric
+type GreedyStrategy struct {
+	repo   *GoodsRepository
+	metric RankingMetric
+}
+
+func NewGreedyStrategy(repo *GoodsRepository, metric RankingMetric) *GreedyStrategy {
+	return &GreedyStrategy{
+		repo:   repo,
+		metric: metric,
+	}
+}
+
+func (s *GreedyStrategy) WithGoods(repo *GoodsRepository) Strategy {
+	return NewGreedyStrategy(repo, s.metric)
+}
+
+func (s *GreedyStrategy) Plan(ctx context.Context, state models.PlayerState) (models.Plan, error) {
+	optimizer := NewOptimizer(s.repo.GetAllGoods(), s.metric).withLifecycles(state.Lifecycles)
+
+	availableGoods := availableGoods(s.repo, state)
+
+	goods := optimizer.GetOptimizedPlan(availableGoods)
+
+	plan := models.Plan{
+		Goods:     goods,
+		Decisions: optimizer.Decisions(),
+	}
+	if _, err := evaluatePlan(s.repo, state, &plan); err != nil {
+		return models.Plan{}, err
+	}
+
+	return plan, nil
+}
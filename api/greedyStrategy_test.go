@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestGreedyStrategySkipsUnownedSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity map[string]int
+		want     []string
+		missing  []string
+	}{
+		{
+			name:     "full farm",
+			capacity: map[string]int{models.SourceField: 4, models.SourceFeedMill: 1, "Chicken": 3, "Bakery": 1},
+			want:     []string{"Corn bread"},
+		},
+		{
+			name:     "no bakery",
+			capacity: map[string]int{models.SourceField: 4, models.SourceFeedMill: 1, "Chicken": 3, "Bakery": 0},
+			missing:  []string{"Bread", "Corn bread"},
+		},
+		{
+			name:     "nothing owned",
+			capacity: map[string]int{},
+			missing:  []string{"Wheat", "Corn", "Chicken feed", "Egg", "Bread", "Corn bread"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := models.PlayerState{Level: 5, Horizon: 24 * time.Hour, Capacity: test.capacity}
+
+			plan, err := NewGreedyStrategy(testRepository(), MetricNetProfit).Plan(context.Background(), state)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, name := range test.want {
+				if plannedQuantity(plan, name) == 0 {
+					t.Errorf("%s not planned", name)
+				}
+			}
+			for _, name := range test.missing {
+				if quantity := plannedQuantity(plan, name); quantity > 0 {
+					t.Errorf("%s planned %d times without its source", name, quantity)
+				}
+			}
+		})
+	}
+}
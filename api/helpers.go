@@ -1,8 +1,9 @@
 package api
 
 import (
-	"sort"
+	"time"
 
+	"github.com/noTirT/hayday-optimizer/base"
 	"github.com/noTirT/hayday-optimizer/models"
 )
 
@@ -14,10 +15,128 @@ func groupGoodsBySource(goods models.HayDayGoodList) map[string]models.HayDayGoo
 	return sourceMap
 }
 
-func sortGoodsByPriceDescending(goods models.HayDayGoodList) {
-	sort.Slice(goods, func(i, j int) bool {
-		return (goods)[i].MaxPrice > (goods)[j].MaxPrice
-	})
+// Goods without a known production time cannot be ranked per hour and score 0
+func coinsPerHour(good models.HayDayGood) float64 {
+	if good.ProductionTime <= 0 {
+		return 0
+	}
+	return float64(good.MaxPrice) / good.ProductionTime.Hours()
+}
+
+func xpPerHour(good models.HayDayGood) float64 {
+	if good.ProductionTime <= 0 {
+		return 0
+	}
+	return float64(good.GainedXP) / good.ProductionTime.Hours()
+}
+
+// Earnings of a plan when every good runs back to back on its own source
+func netCoinsPerHour(goods models.PlannedGoodList) float64 {
+	total := 0.0
+	for _, good := range goods {
+		if good.ProductionTime > 0 {
+			total += float64(good.NetValue) / good.ProductionTime.Hours()
+		}
+	}
+	return total
+}
+
+// Goods unlocked at the player's level that one of their sources can make
+func availableGoods(repo *GoodsRepository, state models.PlayerState) models.HayDayGoodList {
+	goods := repo.GetGoodsByLevel(state.Level)
+	if state.Capacity == nil {
+		return goods
+	}
+
+	var result models.HayDayGoodList
+	for _, good := range goods {
+		if state.Capacity[good.Source] > 0 {
+			result = append(result, good)
+		}
+	}
+	return result
+}
+
+// Lines of a source the player runs, a typical farm at their level when the
+// state does not say
+func sourceCapacity(state models.PlayerState, source string) int {
+	if state.Capacity == nil {
+		return models.DefaultSourceCount(state.Level, source)
+	}
+	return state.Capacity[source]
+}
+
+// Experience from the start of one level to the start of another, every
+// level in between needs an entry in the table
+func xpBetweenLevels(xpToNextLevel map[int]int, from, to int) (int, error) {
+	total := 0
+	for level := from; level < to; level++ {
+		xp, exists := xpToNextLevel[level]
+		if !exists || xp <= 0 {
+			return 0, base.ErrMissingLevelXP
+		}
+		total += xp
+	}
+	return total, nil
+}
+
+// Storage contents from a request, every good has to exist and no count may
+// be negative
+func validateInventory(repo *GoodsRepository, inventory map[string]int) error {
+	for name, amount := range inventory {
+		if amount < 0 {
+			return base.ErrInvalidInventory
+		}
+		if _, err := repo.GetGoodByName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lines and stock the simulator starts from, the same the strategies planned with
+func simulationSetup(repo *GoodsRepository, state models.PlayerState) models.SimulationSetup {
+	capacity := make(map[string]int)
+	slots := make(map[string]int)
+	for source := range groupGoodsBySource(availableGoods(repo, state)) {
+		capacity[source] = sourceCapacity(state, source)
+		slots[source] = queueSlots(state, source)
+	}
+
+	return models.SimulationSetup{
+		Horizon:       state.Horizon,
+		Capacity:      capacity,
+		QueueSlots:    slots,
+		VisitInterval: state.VisitInterval,
+		Inventory:     state.Inventory,
+	}
+}
+
+// Time a line of the good's source is taken by one job of it
+func jobCycle(state models.PlayerState, good models.HayDayGood) time.Duration {
+	return models.JobCycle(good.ProductionTime, state.VisitInterval, queueSlots(state, good.Source))
+}
+
+// Jobs a line can run back to back without the player. Only machines have a
+// queue, everything else holds a single job.
+func queueSlots(state models.PlayerState, source string) int {
+	if !models.IsMachineSource(source) {
+		return 1
+	}
+	if configured := state.QueueSlots[source]; configured > 0 {
+		return configured
+	}
+	return defaultQueueSlots
+}
+
+// Coins every harvest of a tree or bush has to set aside for replanting, trees
+// and bushes are not machines that last forever
+func replantCost(lifecycles map[string]models.TreeLifecycle, good models.HayDayGood) float64 {
+	if !models.IsTreeOrBushSource(good.Source) {
+		return 0
+	}
+	lifecycle, _ := lifecycles[good.Source].WithDefaults(good)
+	return lifecycle.ReplantCostPerHarvest()
 }
 
 func isBaseProduct(good models.HayDayGood) bool {
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Small farm the planner tests run on: crops, feed for the chickens, eggs and
+// two bakery goods, one of them made from eggs
+func testGoods() models.HayDayGoodList {
+	good := func(name, source string, level, price int, minutes, xp int, ingredients ...models.Ingredient) models.HayDayGood {
+		return models.HayDayGood{
+			ID:             testGoodID(name),
+			Name:           name,
+			RequiredLevel:  level,
+			MaxPrice:       price,
+			ProductionTime: time.Duration(minutes) * time.Minute,
+			GainedXP:       xp,
+			Ingredients:    ingredients,
+			Source:         source,
+		}
+	}
+	needs := func(name string, amount int) models.Ingredient {
+		return models.Ingredient{ProductID: testGoodID(name), ProductName: name, Amount: amount}
+	}
+
+	return models.HayDayGoodList{
+		good("Wheat", models.SourceField, 1, 3, 2, 1),
+		good("Corn", models.SourceField, 2, 7, 5, 1),
+		good("Chicken feed", models.SourceFeedMill, 1, 7, 5, 1, needs("Wheat", 2), needs("Corn", 1)),
+		good("Egg", "Chicken", 1, 18, 20, 2, needs("Chicken feed", 1)),
+		good("Bread", "Bakery", 2, 21, 5, 3, needs("Wheat", 3)),
+		good("Corn bread", "Bakery", 5, 72, 30, 10, needs("Corn", 2), needs("Egg", 2)),
+	}
+}
+
+func testGoodID(name string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name))
+}
+
+func testRepository() *GoodsRepository {
+	return newGoodsRepositoryFromList(testGoods())
+}
+
+// Every controller on one router, profiles live in a directory of the test
+func testRouter(t *testing.T) *http.ServeMux {
+	t.Helper()
+
+	repo := testRepository()
+	profiles := testProfileRepository(t, t.TempDir())
+	strategies := NewDefaultStrategyRegistry(repo)
+
+	router := http.NewServeMux()
+	NewGoodsController(repo, strategies).Init(router)
+	NewProfileController(profiles, repo, strategies).Init(router)
+	NewFarmController(repo, profiles, strategies).Init(router)
+	NewOrderController(repo, profiles).Init(router)
+	NewShopController(repo).Init(router)
+	NewUpgradeController(repo, profiles, strategies).Init(router)
+	NewDerbyController(repo, profiles).Init(router)
+	return router
+}
+
+func serve(router http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder
+}
+
+type handlerTest struct {
+	name   string
+	method string
+	path   string
+	body   string
+	status int
+}
+
+func runHandlerTests(t *testing.T, router http.Handler, tests []handlerTest) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := serve(router, test.method, test.path, test.body)
+			if recorder.Code != test.status {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, test.status, recorder.Body)
+			}
+		})
+	}
+}
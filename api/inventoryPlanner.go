@@ -0,0 +1,110 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const (
+	storageSilo = "Silo"
+	storageBarn = "Barn"
+)
+
+// Settles a plan against the player's stock: ingredient needs are served from
+// the inventory first and the final output is checked against barn and silo
+type InventoryPlanner struct {
+	goodsMap    map[uuid.UUID]models.HayDayGood
+	goodsByName map[string]models.HayDayGood
+	expander    *BOMExpander
+}
+
+func NewInventoryPlanner(allGoods models.HayDayGoodList) *InventoryPlanner {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	goodsByName := make(map[string]models.HayDayGood)
+	for _, good := range allGoods {
+		goodsMap[good.ID] = good
+		goodsByName[good.Name] = good
+	}
+
+	return &InventoryPlanner{
+		goodsMap:    goodsMap,
+		goodsByName: goodsByName,
+		expander:    NewBOMExpander(allGoods),
+	}
+}
+
+func (p *InventoryPlanner) Apply(plan *models.Plan, state models.PlayerState) {
+	stock := stockByID(p.goodsByName, state.Inventory)
+	output := finalOutput(plan.Goods)
+
+	needs := make(map[uuid.UUID]*models.IngredientNeed)
+	for _, planned := range plan.Goods {
+		p.expander.ExpandNeeds(planned.HayDayGood, output[planned.ID], stock, needs)
+	}
+
+	plan.Ingredients = []models.IngredientNeed{}
+	for _, need := range needs {
+		plan.Ingredients = append(plan.Ingredients, *need)
+	}
+	sort.Slice(plan.Ingredients, func(i, j int) bool {
+		return plan.Ingredients[i].Name < plan.Ingredients[j].Name
+	})
+
+	// Whatever is left in stock stays in storage next to the new output
+	for id, amount := range stock {
+		output[id] += amount
+	}
+	plan.Storage = p.storageUsage(output, state)
+}
+
+// Units of every planned good that remain once the plan's own recipes took
+// their share. Quantities cover the horizon, the same as in planDemand.
+func finalOutput(goods models.PlannedGoodList) map[uuid.UUID]int {
+	output := make(map[uuid.UUID]int)
+	for _, planned := range goods {
+		output[planned.ID] += planned.Quantity
+	}
+	for _, planned := range goods {
+		for _, ingredient := range planned.Ingredients {
+			if _, isPlanned := output[ingredient.ProductID]; isPlanned {
+				output[ingredient.ProductID] -= ingredient.Amount * models.Batches(planned.Source, planned.Quantity)
+			}
+		}
+	}
+	for id, amount := range output {
+		output[id] = max(amount, 0)
+	}
+	return output
+}
+
+func stockByID(goodsByName map[string]models.HayDayGood, inventory map[string]int) map[uuid.UUID]int {
+	stock := make(map[uuid.UUID]int)
+	for name, amount := range inventory {
+		if good, exists := goodsByName[name]; exists {
+			stock[good.ID] += amount
+		}
+	}
+	return stock
+}
+
+func (p *InventoryPlanner) storageUsage(contents map[uuid.UUID]int, state models.PlayerState) []models.StorageUsage {
+	silo := models.StorageUsage{Storage: storageSilo, Capacity: state.SiloCapacity}
+	barn := models.StorageUsage{Storage: storageBarn, Capacity: state.BarnCapacity}
+
+	for id, amount := range contents {
+		// Crops go to the silo, everything else to the barn
+		if p.goodsMap[id].Source == models.SourceField {
+			silo.Used += amount
+		} else {
+			barn.Used += amount
+		}
+	}
+
+	// A capacity of zero means the player did not tell us
+	silo.Overflow = silo.Capacity > 0 && silo.Used > silo.Capacity
+	barn.Overflow = barn.Capacity > 0 && barn.Used > barn.Capacity
+
+	return []models.StorageUsage{silo, barn}
+}
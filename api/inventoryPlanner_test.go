@@ -0,0 +1,55 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestInventoryPlannerServesStockFirst(t *testing.T) {
+	// Three corn breads take six corn and six eggs. Four eggs are in the barn,
+	// the other two need two chicken feed, which is one batch of the mill.
+	repo := testRepository()
+	cornBread, err := repo.GetGoodByName("Corn bread")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan := models.Plan{Goods: models.PlannedGoodList{{HayDayGood: *cornBread, Quantity: 3}}}
+	state := models.PlayerState{
+		Level:        5,
+		Inventory:    map[string]int{"Egg": 4, "Corn": 10},
+		SiloCapacity: 2,
+	}
+
+	NewInventoryPlanner(repo.GetAllGoods()).Apply(&plan, state)
+
+	want := []models.IngredientNeed{
+		{Name: "Chicken feed", Needed: 2, FromInventory: 0, ToProduce: 2},
+		{Name: "Corn", Needed: 7, FromInventory: 7, ToProduce: 0},
+		{Name: "Egg", Needed: 6, FromInventory: 4, ToProduce: 2},
+		{Name: "Wheat", Needed: 2, FromInventory: 0, ToProduce: 2},
+	}
+	if len(plan.Ingredients) != len(want) {
+		t.Fatalf("ingredients = %+v, want %+v", plan.Ingredients, want)
+	}
+	for i, need := range plan.Ingredients {
+		if need != want[i] {
+			t.Errorf("ingredient %d = %+v, want %+v", i, need, want[i])
+		}
+	}
+
+	// Three corn are left in the silo, the barn only gets the corn bread. A
+	// barn without a capacity never overflows.
+	storage := []models.StorageUsage{
+		{Storage: storageSilo, Capacity: 2, Used: 3, Overflow: true},
+		{Storage: storageBarn, Capacity: 0, Used: 3, Overflow: false},
+	}
+	if len(plan.Storage) != len(storage) {
+		t.Fatalf("storage = %+v, want %+v", plan.Storage, storage)
+	}
+	for i, usage := range plan.Storage {
+		if usage != storage[i] {
+			t.Errorf("storage %d = %+v, want %+v", i, usage, storage[i])
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+	"github.com/noTirT/hayday-optimizer/solver"
+)
+
+const (
+	defaultPlanningHorizon = 24 * time.Hour
+	lpSearchNodes          = 2000
+	lpSearchTimeout        = 10 * time.Second
+)
+
+// Solves the production mix exactly: every source is a capacity constraint
+// over the planning horizon and every recipe consumes units of its ingredients
+type LPStrategy struct {
+	repo *GoodsRepository
+}
+
+func NewLPStrategy(repo *GoodsRepository) *LPStrategy {
+	return &LPStrategy{
+		repo: repo,
+	}
+}
+
+func (s *LPStrategy) WithGoods(repo *GoodsRepository) Strategy {
+	return NewLPStrategy(repo)
+}
+
+func (s *LPStrategy) Plan(ctx context.Context, state models.PlayerState) (models.Plan, error) {
+	horizon := state.Horizon
+	if horizon <= 0 {
+		horizon = defaultPlanningHorizon
+	}
+
+	goods := producibleGoods(availableGoods(s.repo, state))
+	problem := buildProductionProblem(goods, state, horizon, func(models.HayDayGood) bool { return true })
+
+	ctx, cancel := context.WithTimeout(ctx, lpSearchTimeout)
+	defer cancel()
+
+	solution, err := solver.SolveMILP(ctx, problem, lpSearchNodes)
+	if err != nil {
+		return models.Plan{}, err
+	}
+
+	optimizer := NewOptimizer(s.repo.GetAllGoods(), MetricNetProfit).withLifecycles(state.Lifecycles)
+
+	plan := models.Plan{
+		Goods:   models.PlannedGoodList{},
+		Optimal: &solution.Optimal,
+	}
+	earnings := make(map[string]float64)
+	for j, good := range goods {
+		quantity := int(math.Round(solution.Values[j]))
+		if quantity == 0 || isExternalGood(good) {
+			continue
+		}
+		earnings[good.Name] = problem.Objective[j]
+		plan.Goods = append(plan.Goods, models.PlannedGood{
+			HayDayGood: good,
+			GrossValue: good.MaxPrice,
+			NetValue:   optimizer.netValue(good),
+			XPPerHour:  xpPerHour(good),
+			Quantity:   quantity,
+		})
+	}
+
+	// The model only counts machine hours. Units the scheduler cannot place
+	// within the horizon are dropped along with what they would have earned.
+	objective := solution.Objective
+	scheduler := NewProductionScheduler(s.repo.GetAllGoods())
+	for round := 0; round < maxFitRounds; round++ {
+		unscheduled := scheduler.Schedule(plan.Goods, state, time.Time{}).Unscheduled
+		if len(unscheduled) == 0 {
+			break
+		}
+		for i, good := range plan.Goods {
+			missing := min(unscheduled[good.Name], good.Quantity)
+			plan.Goods[i].Quantity -= missing
+			objective -= float64(missing) * earnings[good.Name]
+		}
+	}
+	kept := models.PlannedGoodList{}
+	for _, good := range plan.Goods {
+		if good.Quantity > 0 {
+			kept = append(kept, good)
+		}
+	}
+	plan.Goods = kept
+	plan.CoinsPerHour = objective / horizon.Hours()
+
+	return plan, nil
+}
+
+// Drop goods with an ingredient that is not available itself. Goods without
+// a known production time stay as outside inputs, see isExternalGood.
+func producibleGoods(goods models.HayDayGoodList) models.HayDayGoodList {
+	result := goods
+	for {
+		available := make(map[uuid.UUID]bool)
+		for _, good := range result {
+			available[good.ID] = true
+		}
+
+		var kept models.HayDayGoodList
+		for _, good := range result {
+			producible := true
+			for _, ingredient := range good.Ingredients {
+				if !available[ingredient.ProductID] {
+					producible = false
+					break
+				}
+			}
+			if producible {
+				kept = append(kept, good)
+			}
+		}
+
+		if len(kept) == len(result) {
+			return kept
+		}
+		result = kept
+	}
+}
+
+// Goods without a known production time, such as ores, fish and some fruit,
+// are gathered outside the production lines. Plans take them at the price
+// they would sell for and never produce or sell them.
+func isExternalGood(good models.HayDayGood) bool {
+	return good.ProductionTime <= 0
+}
+
+// One variable per good counting the produced units. Selling a unit earns its
+// price, consuming it as an ingredient gives that price up again. Goods that
+// are not sold are worth nothing on their own and only feed other recipes.
+// Ingredients already in storage can be consumed on top of what is produced.
+func buildProductionProblem(goods models.HayDayGoodList, state models.PlayerState, horizon time.Duration, sold func(models.HayDayGood) bool) solver.Problem {
+	index := make(map[uuid.UUID]int)
+	for j, good := range goods {
+		index[good.ID] = j
+	}
+
+	objective := make([]float64, len(goods))
+	var unmakeable []int
+	sourceRows := make(map[string][]float64)
+	flowRows := make(map[int][]float64)
+
+	for j, good := range goods {
+		if isExternalGood(good) {
+			objective[j] -= float64(good.MaxPrice)
+			continue
+		}
+		if sold(good) {
+			objective[j] += float64(good.MaxPrice)
+		}
+		objective[j] -= replantCost(state.Lifecycles, good)
+
+		if sourceRows[good.Source] == nil {
+			sourceRows[good.Source] = make([]float64, len(goods))
+		}
+		// A run makes a whole batch, every unit takes its share of the time
+		// the line is taken and of the ingredients
+		yield := float64(models.BatchYield(good.Source))
+		sourceRows[good.Source][j] = jobCycle(state, good).Hours() / yield
+
+		for _, ingredient := range good.Ingredients {
+			k, known := index[ingredient.ProductID]
+			if !known {
+				// Without its ingredient in the model the good cannot be made
+				unmakeable = append(unmakeable, j)
+				continue
+			}
+			if sold(goods[k]) && !isExternalGood(goods[k]) {
+				objective[j] -= float64(ingredient.Amount*goods[k].MaxPrice) / yield
+			}
+
+			// Units consumed by recipes can never exceed units produced
+			if flowRows[k] == nil {
+				flowRows[k] = make([]float64, len(goods))
+				flowRows[k][k] = -1
+			}
+			flowRows[k][j] += float64(ingredient.Amount) / yield
+		}
+	}
+
+	var constraints []solver.Constraint
+	for source, row := range sourceRows {
+		constraints = append(constraints, solver.Constraint{
+			Coefficients: row,
+			Bound:        horizon.Hours() * float64(sourceCapacity(state, source)),
+		})
+	}
+	for _, j := range unmakeable {
+		row := make([]float64, len(goods))
+		row[j] = 1
+		constraints = append(constraints, solver.Constraint{Coefficients: row, Bound: 0})
+	}
+	for k, row := range flowRows {
+		constraints = append(constraints, solver.Constraint{
+			Coefficients: row,
+			Bound:        float64(max(state.Inventory[goods[k].Name], 0)),
+		})
+	}
+
+	return solver.Problem{
+		Objective:   objective,
+		Constraints: constraints,
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/models"
+	"github.com/noTirT/hayday-optimizer/solver"
+)
+
+func plannedQuantity(plan models.Plan, name string) int {
+	for _, good := range plan.Goods {
+		if good.Name == name {
+			return good.Quantity
+		}
+	}
+	return 0
+}
+
+func TestLPStrategyUsesInventory(t *testing.T) {
+	// One field cannot grow the wheat a bakery working a full hour needs. With
+	// two queue slots and a visit every ten minutes the bakery bakes every
+	// five, the field is only replanted every ten.
+	state := models.PlayerState{
+		Level:         2,
+		Horizon:       time.Hour,
+		VisitInterval: 10 * time.Minute,
+		Capacity:      map[string]int{models.SourceField: 1, "Bakery": 1},
+	}
+
+	without, err := NewLPStrategy(testRepository()).Plan(context.Background(), state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state.Inventory = map[string]int{"Wheat": 36}
+	with, err := NewLPStrategy(testRepository()).Plan(context.Background(), state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := plannedQuantity(with, "Bread"); got != 12 {
+		t.Errorf("bread with wheat in stock = %d, want the bakery's 12", got)
+	}
+	if plannedQuantity(without, "Bread") >= plannedQuantity(with, "Bread") {
+		t.Errorf("bread without stock = %d, want fewer than with stock", plannedQuantity(without, "Bread"))
+	}
+}
+
+func TestLPStrategyVisitIntervalLimitsTurnover(t *testing.T) {
+	// Wheat grows in two minutes but waits on the field until the next visit
+	state := models.PlayerState{
+		Level:         1,
+		Horizon:       24 * time.Hour,
+		VisitInterval: time.Hour,
+		Capacity:      map[string]int{models.SourceField: 10},
+	}
+
+	plan, err := NewLPStrategy(testRepository()).Plan(context.Background(), state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := plannedQuantity(plan, "Wheat"); got != 240 {
+		t.Errorf("wheat = %d, want 10 fields harvested 24 times", got)
+	}
+}
+
+func TestBuildProductionProblemWithoutIngredient(t *testing.T) {
+	// Bread alone, its wheat is not part of the model
+	var goods models.HayDayGoodList
+	for _, good := range testGoods() {
+		if good.Name == "Bread" {
+			goods = append(goods, good)
+		}
+	}
+
+	problem := buildProductionProblem(goods, models.PlayerState{Level: 10}, time.Hour, func(models.HayDayGood) bool { return true })
+	solution, err := solver.SolveLP(problem)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if solution.Values[0] > 0 || solution.Objective > 0 {
+		t.Errorf("bread = %v earning %v, want none without wheat", solution.Values[0], solution.Objective)
+	}
+}
+
+func TestLPStrategyBuysExternalIngredients(t *testing.T) {
+	// Ore comes out of the mine without a production time. The smelter still
+	// turns it into bars, paying what the ore would sell for.
+	ore := models.HayDayGood{ID: testGoodID("Silver ore"), Name: "Silver ore", RequiredLevel: 1, MaxPrice: 10, Source: "Mine"}
+	bar := models.HayDayGood{
+		ID:             testGoodID("Silver bar"),
+		Name:           "Silver bar",
+		RequiredLevel:  1,
+		MaxPrice:       100,
+		ProductionTime: time.Hour,
+		GainedXP:       5,
+		Ingredients:    []models.Ingredient{{ProductID: ore.ID, ProductName: ore.Name, Amount: 2}},
+		Source:         "Smelter",
+	}
+	state := models.PlayerState{
+		Level:    1,
+		Horizon:  2 * time.Hour,
+		Capacity: map[string]int{"Mine": 1, "Smelter": 1},
+	}
+
+	plan, err := NewLPStrategy(newGoodsRepositoryFromList(models.HayDayGoodList{ore, bar})).Plan(context.Background(), state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := plannedQuantity(plan, "Silver bar"); got != 2 {
+		t.Errorf("silver bars = %d, want 2", got)
+	}
+	if len(plan.Goods) != 1 {
+		t.Errorf("planned goods = %d, want only the bars", len(plan.Goods))
+	}
+	if plan.CoinsPerHour != 80 {
+		t.Errorf("coins per hour = %v, want 80 after the ore", plan.CoinsPerHour)
+	}
+}
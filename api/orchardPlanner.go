@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Values trees and bushes over their whole life instead of as machines that
+// never wear out, and recommends how many of each to keep. Plots first go to
+// the fruit the machines of a strategy's plan consume, the rest to the plant
+// with the best amortised profit.
+type OrchardPlanner struct {
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
+	expander   *BOMExpander
+}
+
+func NewOrchardPlanner(repo *GoodsRepository, strategies *StrategyRegistry) *OrchardPlanner {
+	return &OrchardPlanner{
+		repo:       repo,
+		strategies: strategies,
+		expander:   NewBOMExpander(repo.GetAllGoods()),
+	}
+}
+
+func (p *OrchardPlanner) Plan(ctx context.Context, state models.PlayerState, request models.OrchardRequest, owned map[string]int) (models.OrchardPlan, error) {
+	if request.Plots < 0 {
+		return models.OrchardPlan{}, base.ErrInvalidQuantity
+	}
+	for _, lifecycle := range request.Lifecycles {
+		if !lifecycle.Valid() {
+			return models.OrchardPlan{}, base.ErrInvalidQuantity
+		}
+	}
+
+	horizon := state.Horizon
+	if horizon <= 0 {
+		horizon = defaultPlanningHorizon
+	}
+	state.Horizon = horizon
+	state.Lifecycles = request.Lifecycles
+
+	plan, err := runStrategy(ctx, p.strategies, request.Strategy, state)
+	if err != nil {
+		return models.OrchardPlan{}, err
+	}
+
+	demand, priority := planDemand(p.expander, plan.Goods, models.IsTreeOrBushSource)
+
+	orchard := models.OrchardPlan{
+		Horizon:  horizon,
+		Plots:    request.Plots,
+		Strategy: plan.Strategy,
+		Trees:    []models.TreeValuation{},
+	}
+
+	valuations := make(map[string]*models.TreeValuation)
+	goods := make(map[string]models.HayDayGood)
+	for source, sourceGoods := range groupGoodsBySource(p.repo.GetGoodsByLevel(state.Level)) {
+		if !models.IsTreeOrBushSource(source) || len(sourceGoods) == 0 {
+			continue
+		}
+
+		good := sourceGoods[0]
+		valuation := p.valuation(source, good, request.Lifecycles[source])
+		if valuation.Regrowth <= 0 {
+			orchard.Unvalued = append(orchard.Unvalued, source)
+			continue
+		}
+		valuation.Demand = demand[good.Name]
+		valuation.Owned = owned[source]
+
+		valuations[source] = &valuation
+		goods[source] = good
+	}
+	sort.Strings(orchard.Unvalued)
+
+	sources := make([]string, 0, len(valuations))
+	for source := range valuations {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		a, b := valuations[sources[i]], valuations[sources[j]]
+		if priority[a.Good] != priority[b.Good] {
+			return priority[a.Good] > priority[b.Good]
+		}
+		if a.AmortisedProfitPerHour != b.AmortisedProfitPerHour {
+			return a.AmortisedProfitPerHour > b.AmortisedProfitPerHour
+		}
+		return a.Source < b.Source
+	})
+
+	remaining := request.Plots
+	for _, source := range sources {
+		valuation := valuations[source]
+		perPlot := int(horizon / valuation.Regrowth)
+		if valuation.Demand == 0 || perPlot == 0 {
+			continue
+		}
+		valuation.Recommended = min((valuation.Demand+perPlot-1)/perPlot, remaining)
+		remaining -= valuation.Recommended
+	}
+
+	// Sources are ordered by demand first, the best one for plain sale is
+	// the one with the highest amortised profit among all of them
+	var best *models.TreeValuation
+	for _, source := range sources {
+		if valuation := valuations[source]; valuation.AmortisedProfitPerHour > 0 && (best == nil || valuation.AmortisedProfitPerHour > best.AmortisedProfitPerHour) {
+			best = valuation
+		}
+	}
+	if best != nil {
+		best.Recommended += remaining
+		remaining = 0
+	}
+	orchard.UnusedPlots = remaining
+
+	for _, source := range sources {
+		valuation := valuations[source]
+		orchard.Profit += float64(valuation.Recommended) * horizonProfit(*valuation, goods[source], horizon)
+		orchard.Trees = append(orchard.Trees, *valuation)
+	}
+
+	return orchard, nil
+}
+
+func (p *OrchardPlanner) valuation(source string, good models.HayDayGood, lifecycle models.TreeLifecycle) models.TreeValuation {
+	// Same defaults the strategies value the fruit with
+	lifecycle, assumed := lifecycle.WithDefaults(good)
+	valuation := models.TreeValuation{
+		Source:           source,
+		Good:             good.Name,
+		Harvests:         *lifecycle.Harvests,
+		ReplantCost:      *lifecycle.ReplantCost,
+		Regrowth:         good.ProductionTime,
+		AssumedLifecycle: assumed,
+	}
+	if lifecycle.RegrowthHours != nil {
+		valuation.Regrowth = time.Duration(*lifecycle.RegrowthHours * float64(time.Hour))
+	}
+	if valuation.Regrowth <= 0 {
+		return valuation
+	}
+
+	valuation.LifetimeProfit = valuation.Harvests*good.MaxPrice - valuation.ReplantCost
+	lifetime := time.Duration(valuation.Harvests) * valuation.Regrowth
+	valuation.AmortisedProfitPerHour = float64(valuation.LifetimeProfit) / lifetime.Hours()
+
+	return valuation
+}
+
+// Profit of one plot over the horizon, replanting as soon as the plant dies
+func horizonProfit(valuation models.TreeValuation, good models.HayDayGood, horizon time.Duration) float64 {
+	harvests := int(horizon / valuation.Regrowth)
+	replants := math.Ceil(float64(harvests) / float64(valuation.Harvests))
+	return float64(harvests*good.MaxPrice) - replants*float64(valuation.ReplantCost)
+}
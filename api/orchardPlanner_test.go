@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func appleTree() models.HayDayGood {
+	return models.HayDayGood{
+		ID:             testGoodID("Apple"),
+		Name:           "Apple",
+		RequiredLevel:  1,
+		MaxPrice:       40,
+		ProductionTime: 4 * time.Hour,
+		Source:         "Apple tree",
+	}
+}
+
+func intValue(value int) *int { return &value }
+
+func hours(value float64) *float64 { return &value }
+
+func TestOrchardPlannerValuation(t *testing.T) {
+	tests := []struct {
+		name        string
+		lifecycle   models.TreeLifecycle
+		replantCost int
+		regrowth    time.Duration
+		profit      int
+		assumed     bool
+	}{
+		{name: "defaults", lifecycle: models.TreeLifecycle{}, replantCost: 40, regrowth: 4 * time.Hour, profit: 80, assumed: true},
+		{name: "free replant", lifecycle: models.TreeLifecycle{Harvests: intValue(3), ReplantCost: intValue(0)}, replantCost: 0, regrowth: 4 * time.Hour, profit: 120},
+		{name: "own regrowth", lifecycle: models.TreeLifecycle{Harvests: intValue(2), ReplantCost: intValue(10), RegrowthHours: hours(2)}, replantCost: 10, regrowth: 2 * time.Hour, profit: 70},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			planner := NewOrchardPlanner(testRepository(), NewDefaultStrategyRegistry(testRepository()))
+
+			valuation := planner.valuation("Apple tree", appleTree(), test.lifecycle)
+
+			if valuation.ReplantCost != test.replantCost {
+				t.Errorf("replant cost = %d, want %d", valuation.ReplantCost, test.replantCost)
+			}
+			if valuation.Regrowth != test.regrowth {
+				t.Errorf("regrowth = %v, want %v", valuation.Regrowth, test.regrowth)
+			}
+			if valuation.LifetimeProfit != test.profit {
+				t.Errorf("lifetime profit = %d, want %d", valuation.LifetimeProfit, test.profit)
+			}
+			if valuation.AssumedLifecycle != test.assumed {
+				t.Errorf("assumed lifecycle = %v, want %v", valuation.AssumedLifecycle, test.assumed)
+			}
+		})
+	}
+}
+
+func TestOrchardPlannerRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		request models.OrchardRequest
+	}{
+		{name: "negative plots", request: models.OrchardRequest{Plots: -1}},
+		{name: "no harvests", request: models.OrchardRequest{Lifecycles: map[string]models.TreeLifecycle{"Apple tree": {Harvests: intValue(0)}}}},
+		{name: "negative replant cost", request: models.OrchardRequest{Lifecycles: map[string]models.TreeLifecycle{"Apple tree": {ReplantCost: intValue(-5)}}}},
+		{name: "negative regrowth", request: models.OrchardRequest{Lifecycles: map[string]models.TreeLifecycle{"Apple tree": {RegrowthHours: hours(-1)}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := newGoodsRepositoryFromList(append(testGoods(), appleTree()))
+			planner := NewOrchardPlanner(repo, NewDefaultStrategyRegistry(repo))
+
+			_, err := planner.Plan(context.Background(), models.PlayerState{Level: 5}, test.request, nil)
+			if !errors.Is(err, base.ErrInvalidQuantity) {
+				t.Errorf("err = %v, want %v", err, base.ErrInvalidQuantity)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type OrderController struct {
+	repo     *GoodsRepository
+	profiles *ProfileRepository
+}
+
+func NewOrderController(repo *GoodsRepository, profiles *ProfileRepository) *OrderController {
+	return &OrderController{
+		repo:     repo,
+		profiles: profiles,
+	}
+}
+
+func (a *OrderController) Init(router *http.ServeMux) {
+	router.HandleFunc("POST /orders/truck/evaluate", a.evaluateTruckOrder)
+	router.HandleFunc("POST /orders/boat/plan", a.planBoatOrder)
+}
+
+func (a *OrderController) evaluateTruckOrder(w http.ResponseWriter, r *http.Request) {
+	var order models.TruckOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	evaluation, err := NewTruckOrderEvaluator(a.repo).Evaluate(order)
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(evaluation)
+}
+
+func (a *OrderController) planBoatOrder(w http.ResponseWriter, r *http.Request) {
+	var order models.BoatOrder
+	if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := models.PlayerState{Level: order.Level}
+	if order.ProfileID != "" {
+		id, err := parseProfileID(order.ProfileID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		profile, err := a.profiles.GetProfileByID(id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		state = playerStateFromProfile(*profile)
+	}
+	state.Inventory = order.Inventory
+
+	plan, err := NewBoatOrderPlanner(a.repo).Plan(order, state, time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(plan)
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOrderControllerStatus(t *testing.T) {
+	deadline := time.Now().Add(6 * time.Hour).UTC().Format(time.RFC3339)
+	passed := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "truck", method: http.MethodPost, path: "/orders/truck/evaluate", body: `{"Level":5,"Goods":[{"Name":"Bread","Amount":2}],"Coins":100}`, status: http.StatusOK},
+		{name: "truck empty item", method: http.MethodPost, path: "/orders/truck/evaluate", body: `{"Level":5,"Goods":[{"Name":"Bread","Amount":0}]}`, status: http.StatusBadRequest},
+		{name: "truck unknown good", method: http.MethodPost, path: "/orders/truck/evaluate", body: `{"Level":5,"Goods":[{"Name":"Gold","Amount":1}]}`, status: http.StatusBadRequest},
+		{name: "boat", method: http.MethodPost, path: "/orders/boat/plan", body: `{"Level":5,"Crates":[{"Good":"Bread","Amount":2}],"Deadline":"` + deadline + `"}`, status: http.StatusOK},
+		{name: "boat deadline passed", method: http.MethodPost, path: "/orders/boat/plan", body: `{"Level":5,"Crates":[{"Good":"Bread","Amount":2}],"Deadline":"` + passed + `"}`, status: http.StatusBadRequest},
+		{name: "boat negative inventory", method: http.MethodPost, path: "/orders/boat/plan", body: `{"Level":5,"Crates":[{"Good":"Bread","Amount":2}],"Deadline":"` + deadline + `","Inventory":{"Wheat":-1}}`, status: http.StatusBadRequest},
+		{name: "boat unknown profile", method: http.MethodPost, path: "/orders/boat/plan", body: `{"ProfileID":"6ba7b811-9dad-11d1-80b4-00c04fd430c8"}`, status: http.StatusNotFound},
+	})
+}
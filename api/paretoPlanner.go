@@ -0,0 +1,155 @@
+package api
+
+import (
+	"sort"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Resolution of the weight grid the frontier is sampled with
+const paretoWeightSteps = 10
+
+// Finds the production mixes that trade coins, experience and production time
+// off against each other. Every weight combination on a grid is handed to the
+// optimizer and the resulting plans that are dominated by another are dropped.
+type ParetoPlanner struct {
+	allGoods models.HayDayGoodList
+}
+
+func NewParetoPlanner(allGoods models.HayDayGoodList) *ParetoPlanner {
+	return &ParetoPlanner{
+		allGoods: allGoods,
+	}
+}
+
+// Weights are optional, when given the frontier also carries the option that
+// suits them best
+func (p *ParetoPlanner) Plan(available models.HayDayGoodList, level int, weights *models.ObjectiveWeights) models.ParetoFrontier {
+	candidates := p.sampleWeights()
+	if weights != nil {
+		candidates = append(candidates, *weights)
+	}
+
+	var options []models.ParetoOption
+	for _, candidate := range candidates {
+		optimizer := NewWeightedOptimizer(p.allGoods, candidate)
+		options = append(options, p.option(optimizer.GetOptimizedPlan(available), candidate))
+	}
+
+	frontier := models.ParetoFrontier{
+		Level:   level,
+		Options: nonDominated(options),
+	}
+
+	sort.SliceStable(frontier.Options, func(i, j int) bool {
+		return frontier.Options[i].CoinsPerHour > frontier.Options[j].CoinsPerHour
+	})
+
+	if weights != nil && len(frontier.Options) > 0 {
+		frontier.Weights = weights
+		frontier.Recommended = recommendOption(frontier.Options, *weights)
+	}
+
+	return frontier
+}
+
+// All weight combinations on the grid except the one that weighs nothing
+func (p *ParetoPlanner) sampleWeights() []models.ObjectiveWeights {
+	var samples []models.ObjectiveWeights
+	for coins := 0; coins <= paretoWeightSteps; coins++ {
+		for xp := 0; coins+xp <= paretoWeightSteps; xp++ {
+			timeSteps := paretoWeightSteps - coins - xp
+			if coins == 0 && xp == 0 && timeSteps == 0 {
+				continue
+			}
+			samples = append(samples, models.ObjectiveWeights{
+				Coins: float64(coins) / paretoWeightSteps,
+				XP:    float64(xp) / paretoWeightSteps,
+				Time:  float64(timeSteps) / paretoWeightSteps,
+			})
+		}
+	}
+	return samples
+}
+
+func (p *ParetoPlanner) option(goods models.PlannedGoodList, weights models.ObjectiveWeights) models.ParetoOption {
+	option := models.ParetoOption{
+		Goods:        goods,
+		CoinsPerHour: netCoinsPerHour(goods),
+		Weights:      weights,
+	}
+	for _, good := range goods {
+		option.XPPerHour += good.XPPerHour
+		option.CycleTime = max(option.CycleTime, good.ProductionTime)
+	}
+	return option
+}
+
+// Keep the first of every set of identical mixes and drop those another mix
+// beats on every objective
+func nonDominated(options []models.ParetoOption) []models.ParetoOption {
+	var result []models.ParetoOption
+	for i, option := range options {
+		keep := true
+		for j, other := range options {
+			if i == j {
+				continue
+			}
+			if dominates(other, option) || (j < i && sameObjectives(other, option)) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			result = append(result, option)
+		}
+	}
+	return result
+}
+
+func dominates(a, b models.ParetoOption) bool {
+	if a.CoinsPerHour < b.CoinsPerHour || a.XPPerHour < b.XPPerHour || a.CycleTime > b.CycleTime {
+		return false
+	}
+	return !sameObjectives(a, b)
+}
+
+func sameObjectives(a, b models.ParetoOption) bool {
+	return a.CoinsPerHour == b.CoinsPerHour && a.XPPerHour == b.XPPerHour && a.CycleTime == b.CycleTime
+}
+
+// Scores every option by its weighted position between the worst and best
+// value on the frontier for each objective
+func recommendOption(options []models.ParetoOption, weights models.ObjectiveWeights) *models.ParetoOption {
+	low, high := options[0], options[0]
+	for _, option := range options[1:] {
+		low.CoinsPerHour = min(low.CoinsPerHour, option.CoinsPerHour)
+		high.CoinsPerHour = max(high.CoinsPerHour, option.CoinsPerHour)
+		low.XPPerHour = min(low.XPPerHour, option.XPPerHour)
+		high.XPPerHour = max(high.XPPerHour, option.XPPerHour)
+		low.CycleTime = min(low.CycleTime, option.CycleTime)
+		high.CycleTime = max(high.CycleTime, option.CycleTime)
+	}
+
+	best := 0
+	bestScore := -1.0
+	for i, option := range options {
+		score := weights.Coins*normalize(option.CoinsPerHour, low.CoinsPerHour, high.CoinsPerHour) +
+			weights.XP*normalize(option.XPPerHour, low.XPPerHour, high.XPPerHour) +
+			weights.Time*(1-normalize(option.CycleTime.Hours(), low.CycleTime.Hours(), high.CycleTime.Hours()))
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	recommended := options[best]
+	return &recommended
+}
+
+// Position of value between low and high, 1 when they are equal
+func normalize(value, low, high float64) float64 {
+	if high <= low {
+		return 1
+	}
+	return (value - low) / (high - low)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+	"github.com/noTirT/hayday-optimizer/solver"
+)
+
+const (
+	quantityTolerance = 1e-6
+	// Rounds of solving and scheduling before quantities that still do not
+	// fit are cut without solving again
+	maxFitRounds = 5
+)
+
+// Runs a plan picked by a greedy strategy through the same capacity and flow
+// model the lp strategy solves, so the figures of all strategies compare. Only
+// the planned goods are sold, the rest of their ingredient chains feeds them.
+// The quantities are then handed to the scheduler, and goods it cannot place
+// within the horizon are capped at what it did place and solved again, goods
+// of which not a single unit fits leave the plan.
+// Goods without a production time are dropped from the plan. Fills in the
+// quantity of every other planned good and the earnings, and returns
+// the experience the whole chain gains per hour.
+func evaluatePlan(repo *GoodsRepository, state models.PlayerState, plan *models.Plan) (float64, error) {
+	horizon := state.Horizon
+	if horizon <= 0 {
+		horizon = defaultPlanningHorizon
+	}
+
+	producible := make(map[uuid.UUID]models.HayDayGood)
+	for _, good := range producibleGoods(availableGoods(repo, state)) {
+		producible[good.ID] = good
+	}
+
+	// Goods gathered outside the production lines are never made, the same
+	// as in plans of the lp strategy
+	made := models.PlannedGoodList{}
+	for _, good := range plan.Goods {
+		if !isExternalGood(good.HayDayGood) {
+			made = append(made, good)
+		}
+	}
+	plan.Goods = made
+
+	planned := make(map[uuid.UUID]bool)
+	chain := make(map[uuid.UUID]bool)
+	var visit func(id uuid.UUID)
+	visit = func(id uuid.UUID) {
+		good, exists := producible[id]
+		if !exists || chain[id] {
+			return
+		}
+		chain[id] = true
+		for _, ingredient := range good.Ingredients {
+			visit(ingredient.ProductID)
+		}
+	}
+	for _, good := range plan.Goods {
+		planned[good.ID] = true
+		visit(good.ID)
+	}
+
+	// Keep the order of the catalogue so the model is the same on every run
+	var goods models.HayDayGoodList
+	index := make(map[uuid.UUID]int)
+	for _, good := range repo.GetAllGoods() {
+		if chain[good.ID] {
+			index[good.ID] = len(goods)
+			goods = append(goods, producible[good.ID])
+		}
+	}
+
+	problem := buildProductionProblem(goods, state, horizon, func(good models.HayDayGood) bool {
+		return planned[good.ID]
+	})
+	scheduler := NewProductionScheduler(repo.GetAllGoods())
+
+	var solution solver.Solution
+	limits := make(map[int]int)
+	for round := 1; ; round++ {
+		capped := problem
+		capped.Constraints = append([]solver.Constraint(nil), problem.Constraints...)
+		for j, limit := range limits {
+			row := make([]float64, len(goods))
+			row[j] = 1
+			capped.Constraints = append(capped.Constraints, solver.Constraint{Coefficients: row, Bound: float64(limit)})
+		}
+
+		var err error
+		solution, err = solver.SolveLP(capped)
+		if err != nil {
+			return 0, err
+		}
+
+		produced := make(map[uuid.UUID]float64)
+		for j, good := range goods {
+			produced[good.ID] = solution.Values[j]
+		}
+		for i := range plan.Goods {
+			// Whole units only, without losing one to rounding noise of the solver
+			plan.Goods[i].Quantity = int(math.Floor(produced[plan.Goods[i].ID] + quantityTolerance))
+		}
+
+		unscheduled := scheduler.Schedule(plan.Goods, state, time.Time{}).Unscheduled
+		fits := true
+		for i, good := range plan.Goods {
+			if missing := unscheduled[good.Name]; missing > 0 {
+				fits = false
+				plan.Goods[i].Quantity = max(good.Quantity-missing, 0)
+				limits[index[good.ID]] = plan.Goods[i].Quantity
+			}
+		}
+		if fits || round == maxFitRounds {
+			break
+		}
+	}
+
+	fitting := models.PlannedGoodList{}
+	for _, good := range plan.Goods {
+		if good.Quantity > 0 {
+			fitting = append(fitting, good)
+		}
+	}
+	plan.Goods = fitting
+
+	xp := 0.0
+	for j, good := range goods {
+		xp += solution.Values[j] * float64(good.GainedXP)
+	}
+	plan.CoinsPerHour = solution.Objective / horizon.Hours()
+
+	return xp / horizon.Hours(), nil
+}
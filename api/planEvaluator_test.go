@@ -0,0 +1,62 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestEvaluatePlanFitsSchedule(t *testing.T) {
+	// Corn bread waits on eggs, which wait on feed, which waits on crops. The
+	// bakery has the hours for more than the chain delivers in time.
+	capacity := map[string]int{models.SourceField: 2, models.SourceFeedMill: 1, "Chicken": 1, "Bakery": 1}
+	tests := []struct {
+		name    string
+		horizon time.Duration
+		planned bool
+	}{
+		{name: "chain longer than the horizon", horizon: 45 * time.Minute, planned: false},
+		{name: "a few hours", horizon: 3 * time.Hour, planned: true},
+		{name: "one day", horizon: 24 * time.Hour, planned: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := testRepository()
+			good, err := repo.GetGoodByName("Corn bread")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			state := models.PlayerState{Level: 5, Horizon: test.horizon, Capacity: capacity}
+			plan := models.Plan{Goods: models.PlannedGoodList{{HayDayGood: *good}}}
+
+			if _, err := evaluatePlan(repo, state, &plan); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := plannedQuantity(plan, "Corn bread") > 0; got != test.planned {
+				t.Fatalf("corn bread planned = %v, want %v", got, test.planned)
+			}
+			schedule := NewProductionScheduler(repo.GetAllGoods()).Schedule(plan.Goods, state, time.Time{})
+			if len(schedule.Unscheduled) != 0 {
+				t.Errorf("unscheduled = %v, want every planned unit to fit", schedule.Unscheduled)
+			}
+		})
+	}
+}
+
+func TestEvaluatePlanDropsExternalGoods(t *testing.T) {
+	ore := models.HayDayGood{ID: testGoodID("Silver ore"), Name: "Silver ore", RequiredLevel: 1, MaxPrice: 10, Source: "Mine"}
+	repo := newGoodsRepositoryFromList(append(testGoods(), ore))
+	state := models.PlayerState{Level: 1, Horizon: time.Hour, Capacity: map[string]int{"Mine": 1}}
+	plan := models.Plan{Goods: models.PlannedGoodList{{HayDayGood: ore}}}
+
+	if _, err := evaluatePlan(repo, state, &plan); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Goods) != 0 {
+		t.Errorf("planned goods = %v, want none", plan.Goods)
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Assigns the jobs needed for a plan to the production slots of every source.
+// A slot is one production line, e.g. one field or one machine, that works
+// through its jobs back to back. A job only starts once its ingredients are done
+// and can only be queued once the line's queue has room for it.
+type ProductionScheduler struct {
+	goodsMap    map[uuid.UUID]models.HayDayGood
+	goodsByName map[string]models.HayDayGood
+	expander    *BOMExpander
+}
+
+type slotState struct {
+	freeAt   time.Duration
+	jobs     []models.ScheduledJob
+	finishes []time.Duration
+	busy     time.Duration
+}
+
+func NewProductionScheduler(allGoods models.HayDayGoodList) *ProductionScheduler {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	goodsByName := make(map[string]models.HayDayGood)
+	for _, good := range allGoods {
+		goodsMap[good.ID] = good
+		goodsByName[good.Name] = good
+	}
+
+	return &ProductionScheduler{
+		goodsMap:    goodsMap,
+		goodsByName: goodsByName,
+		expander:    NewBOMExpander(allGoods),
+	}
+}
+
+func (s *ProductionScheduler) Schedule(plan models.PlannedGoodList, state models.PlayerState, start time.Time) models.ProductionSchedule {
+	horizon := state.Horizon
+	if horizon <= 0 {
+		horizon = defaultPlanningHorizon
+	}
+
+	schedule := models.ProductionSchedule{
+		Start:       start,
+		Horizon:     horizon,
+		Timelines:   []models.SlotTimeline{},
+		Unscheduled: make(map[string]int),
+		External:    make(map[string]int),
+	}
+
+	stock := stockByID(s.goodsByName, state.Inventory)
+	jobs := s.countJobs(plan, stock)
+
+	// Units already in storage are available right away
+	finished := make(map[uuid.UUID][]time.Duration)
+	for id, amount := range stock {
+		finished[id] = make([]time.Duration, max(amount, 0))
+	}
+	consumed := make(map[uuid.UUID]int)
+
+	slots := make(map[string][]*slotState)
+	queues := make(map[string]int)
+	pending := make(map[uuid.UUID]int)
+	depths := make(map[uuid.UUID]int)
+
+	var goods models.HayDayGoodList
+	for id, count := range jobs {
+		good := s.goodsMap[id]
+		if count <= 0 {
+			continue
+		}
+		// Outside inputs are at hand from the start
+		if isExternalGood(good) {
+			schedule.External[good.Name] += count
+			finished[id] = append(finished[id], make([]time.Duration, count)...)
+			continue
+		}
+
+		// Goods without a line to make them on never finish, and neither
+		// does anything made from them
+		lines := sourceCapacity(state, good.Source)
+		if lines <= 0 {
+			schedule.Unscheduled[good.Name] += count
+			continue
+		}
+
+		goods = append(goods, good)
+		pending[id] = models.Batches(good.Source, count)
+		s.depth(good, depths, make(map[uuid.UUID]bool))
+
+		if slots[good.Source] == nil {
+			for range lines {
+				slots[good.Source] = append(slots[good.Source], &slotState{jobs: []models.ScheduledJob{}})
+			}
+			queues[good.Source] = queueSlots(state, good.Source)
+		}
+	}
+	sort.Slice(goods, func(i, j int) bool {
+		return goods[i].Name < goods[j].Name
+	})
+
+	// List scheduling: always place the job that can start earliest, so sources
+	// interleave the goods they make instead of working through one at a time
+	for {
+		var next models.HayDayGood
+		var nextSlot *slotState
+		var nextStart, nextReady time.Duration
+		found := false
+
+		for _, good := range goods {
+			if pending[good.ID] == 0 {
+				continue
+			}
+			ready, available := s.ingredientsReady(good, finished, consumed)
+			if !available {
+				continue
+			}
+
+			slot := earliestFreeSlot(slots[good.Source])
+			jobStart := max(slot.freeAt, ready)
+			if jobStart+good.ProductionTime > horizon {
+				continue
+			}
+
+			better := !found || jobStart < nextStart ||
+				(jobStart == nextStart && depths[good.ID] < depths[next.ID]) ||
+				(jobStart == nextStart && depths[good.ID] == depths[next.ID] && pending[good.ID] > pending[next.ID])
+			if better {
+				next, nextSlot, nextStart, nextReady, found = good, slot, jobStart, ready, true
+			}
+		}
+		if !found {
+			break
+		}
+
+		for _, ingredient := range next.Ingredients {
+			consumed[ingredient.ProductID] += ingredient.Amount
+		}
+
+		// The queue holds the running job too, so a job fits once the one that
+		// many places ahead of it is done
+		queueFrom := nextReady
+		if queued := len(nextSlot.finishes); queued >= queues[next.Source] {
+			queueFrom = max(queueFrom, nextSlot.finishes[queued-queues[next.Source]])
+		}
+
+		jobFinish := nextStart + next.ProductionTime
+		nextSlot.freeAt = jobFinish
+		nextSlot.busy += next.ProductionTime
+		nextSlot.finishes = append(nextSlot.finishes, jobFinish)
+		nextSlot.jobs = append(nextSlot.jobs, models.ScheduledJob{
+			Good:      next.Name,
+			Amount:    models.BatchYield(next.Source),
+			QueueFrom: start.Add(queueFrom),
+			Start:     start.Add(nextStart),
+			Finish:    start.Add(jobFinish),
+		})
+		pending[next.ID]--
+
+		// Consumers take units in the order they come off the slots
+		for range models.BatchYield(next.Source) {
+			finished[next.ID] = append(finished[next.ID], jobFinish)
+		}
+		sort.Slice(finished[next.ID], func(i, j int) bool {
+			return finished[next.ID][i] < finished[next.ID][j]
+		})
+	}
+
+	for _, good := range goods {
+		if pending[good.ID] > 0 {
+			schedule.Unscheduled[good.Name] += pending[good.ID] * models.BatchYield(good.Source)
+		}
+	}
+
+	sources := make([]string, 0, len(slots))
+	for source := range slots {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		for i, slot := range slots[source] {
+			schedule.Timelines = append(schedule.Timelines, models.SlotTimeline{
+				Source: source,
+				Slot:   i,
+				Jobs:   slot.jobs,
+				Busy:   slot.busy,
+			})
+		}
+	}
+
+	return schedule
+}
+
+// Units per good: the plan's own output plus every ingredient missing from stock
+func (s *ProductionScheduler) countJobs(plan models.PlannedGoodList, stock map[uuid.UUID]int) map[uuid.UUID]int {
+	jobs := finalOutput(plan)
+
+	remaining := make(map[uuid.UUID]int)
+	for id, amount := range stock {
+		remaining[id] = amount
+	}
+
+	needs := make(map[uuid.UUID]*models.IngredientNeed)
+	for _, planned := range plan {
+		s.expander.ExpandNeeds(planned.HayDayGood, jobs[planned.ID], remaining, needs)
+	}
+	for id, need := range needs {
+		jobs[id] += need.ToProduce
+	}
+
+	return jobs
+}
+
+func (s *ProductionScheduler) depth(good models.HayDayGood, depths map[uuid.UUID]int, visited map[uuid.UUID]bool) int {
+	if depth, known := depths[good.ID]; known {
+		return depth
+	}
+	// Prevent infinite recursion with cycles
+	if visited[good.ID] {
+		return 0
+	}
+	visited[good.ID] = true
+
+	depth := 0
+	for _, ingredient := range good.Ingredients {
+		if ingredientGood, exists := s.goodsMap[ingredient.ProductID]; exists {
+			depth = max(depth, s.depth(ingredientGood, depths, visited)+1)
+		}
+	}
+
+	depths[good.ID] = depth
+	return depth
+}
+
+// Moment the next batch of ingredients for a good is complete
+func (s *ProductionScheduler) ingredientsReady(good models.HayDayGood, finished map[uuid.UUID][]time.Duration, consumed map[uuid.UUID]int) (time.Duration, bool) {
+	var ready time.Duration
+	for _, ingredient := range good.Ingredients {
+		last := consumed[ingredient.ProductID] + ingredient.Amount - 1
+		if last >= len(finished[ingredient.ProductID]) {
+			return 0, false
+		}
+		ready = max(ready, finished[ingredient.ProductID][last])
+	}
+	return ready, true
+}
+
+func earliestFreeSlot(slots []*slotState) *slotState {
+	slot := slots[0]
+	for _, candidate := range slots[1:] {
+		if candidate.freeAt < slot.freeAt {
+			slot = candidate
+		}
+	}
+	return slot
+}
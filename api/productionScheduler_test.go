@@ -0,0 +1,77 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func plannedTestGood(name string, quantity int) models.PlannedGood {
+	for _, good := range testGoods() {
+		if good.Name == name {
+			return models.PlannedGood{HayDayGood: good, Quantity: quantity}
+		}
+	}
+	panic("no test good named " + name)
+}
+
+func TestScheduleRunsChainOnOwnedLines(t *testing.T) {
+	state := models.PlayerState{
+		Level:    10,
+		Horizon:  4 * time.Hour,
+		Capacity: map[string]int{models.SourceField: 4, models.SourceFeedMill: 1, "Chicken": 2, "Bakery": 1},
+	}
+
+	schedule := NewProductionScheduler(testGoods()).Schedule(models.PlannedGoodList{plannedTestGood("Corn bread", 1)}, state, time.Time{})
+
+	if len(schedule.Unscheduled) != 0 {
+		t.Fatalf("unscheduled = %v, want none", schedule.Unscheduled)
+	}
+	made := make(map[string]int)
+	for _, timeline := range schedule.Timelines {
+		for _, job := range timeline.Jobs {
+			made[job.Good] += job.Amount
+		}
+	}
+	if made["Corn bread"] != 1 || made["Egg"] != 2 {
+		t.Errorf("made %v, want 1 Corn bread from 2 Egg", made)
+	}
+}
+
+func TestScheduleWithoutLinesForSource(t *testing.T) {
+	// No bakery, so the bread cannot be made even though wheat can
+	state := models.PlayerState{
+		Level:    10,
+		Horizon:  4 * time.Hour,
+		Capacity: map[string]int{models.SourceField: 6},
+	}
+
+	schedule := NewProductionScheduler(testGoods()).Schedule(models.PlannedGoodList{plannedTestGood("Bread", 2)}, state, time.Time{})
+
+	if schedule.Unscheduled["Bread"] != 2 {
+		t.Errorf("unscheduled bread = %d, want 2", schedule.Unscheduled["Bread"])
+	}
+	for _, timeline := range schedule.Timelines {
+		if timeline.Source == "Bakery" {
+			t.Errorf("got a bakery timeline without owning a bakery")
+		}
+		if timeline.Jobs == nil {
+			t.Errorf("%s slot %d has nil jobs, want an empty list", timeline.Source, timeline.Slot)
+		}
+	}
+}
+
+func TestScheduleIgnoresNegativeStock(t *testing.T) {
+	state := models.PlayerState{
+		Level:     10,
+		Horizon:   time.Hour,
+		Inventory: map[string]int{"Wheat": -4},
+	}
+
+	schedule := NewProductionScheduler(testGoods()).Schedule(models.PlannedGoodList{plannedTestGood("Bread", 1)}, state, time.Time{})
+
+	if len(schedule.Unscheduled) != 0 {
+		t.Errorf("unscheduled = %v, want none", schedule.Unscheduled)
+	}
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+	"github.com/noTirT/hayday-optimizer/simulation"
+)
+
+type ProfileController struct {
+	repo       *ProfileRepository
+	goods      *GoodsRepository
+	strategies *StrategyRegistry
+}
+
+func NewProfileController(repo *ProfileRepository, goods *GoodsRepository, strategies *StrategyRegistry) *ProfileController {
+	return &ProfileController{
+		repo:       repo,
+		goods:      goods,
+		strategies: strategies,
+	}
+}
+
+func (a *ProfileController) Init(router *http.ServeMux) {
+	router.HandleFunc("GET /profiles", a.getProfiles)
+	router.HandleFunc("POST /profiles", a.createProfile)
+	router.HandleFunc("GET /profiles/{id}", a.getProfile)
+	router.HandleFunc("PUT /profiles/{id}", a.updateProfile)
+	router.HandleFunc("DELETE /profiles/{id}", a.deleteProfile)
+	router.HandleFunc("GET /profiles/{id}/strategy", a.getProfileStrategy)
+	router.HandleFunc("GET /profiles/{id}/strategy/simulation", a.simulateProfileStrategy)
+}
+
+func (a *ProfileController) getProfiles(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(a.repo.GetAllProfiles())
+}
+
+func (a *ProfileController) createProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := a.decodeProfile(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := a.repo.CreateProfile(profile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (a *ProfileController) getProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := a.profileFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(profile)
+}
+
+func (a *ProfileController) updateProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := parseProfileID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	profile, err := a.decodeProfile(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	profile.ID = id
+
+	updated, err := a.repo.UpdateProfile(profile)
+	if errors.Is(err, base.ErrNoProfileByIDFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (a *ProfileController) deleteProfile(w http.ResponseWriter, r *http.Request) {
+	id, err := parseProfileID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	err = a.repo.DeleteProfile(id)
+	if errors.Is(err, base.ErrNoProfileByIDFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *ProfileController) getProfileStrategy(w http.ResponseWriter, r *http.Request) {
+	profile, err := a.profileFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	state := playerStateFromProfile(*profile)
+
+	hours, err := parseOptionalInt(r, "hours")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	state.Horizon = time.Duration(hours) * time.Hour
+	state.Explain = r.URL.Query().Get("explain") == "true"
+
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := runStrategy(r.Context(), a.strategies, strategyName, state)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(plan)
+}
+
+func (a *ProfileController) simulateProfileStrategy(w http.ResponseWriter, r *http.Request) {
+	profile, err := a.profileFromPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	state := playerStateFromProfile(*profile)
+
+	hours, err := parseOptionalInt(r, "hours")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	state.Horizon = time.Duration(hours) * time.Hour
+
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	timelineLimit, err := parseTimelineLimit(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := runStrategy(r.Context(), a.strategies, strategyName, state)
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	setup := simulationSetup(a.goods, state)
+	setup.TimelineLimit = timelineLimit
+	simulator := simulation.NewFarmSimulator(a.goods.GetAllGoods(), setup)
+
+	json.NewEncoder(w).Encode(simulator.Run(plan.Goods))
+}
+
+func (a *ProfileController) profileFromPath(r *http.Request) (*models.PlayerProfile, error) {
+	id, err := parseProfileID(r.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.GetProfileByID(id)
+}
+
+func (a *ProfileController) decodeProfile(r *http.Request) (models.PlayerProfile, error) {
+	var profile models.PlayerProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		return profile, base.ErrFailedJSONParse
+	}
+	if profile.Level < 1 || profile.Fields < 0 {
+		return profile, base.ErrInvalidProfile
+	}
+	return profile, a.validateSources(profile)
+}
+
+// Every named source has to exist in the goods data and be of the kind the
+// field it is listed under expects, counts cannot be negative
+func (a *ProfileController) validateSources(profile models.PlayerProfile) error {
+	known := groupGoodsBySource(a.goods.GetAllGoods())
+
+	for _, source := range profile.OwnedSources {
+		if _, exists := known[source]; !exists || !models.IsMachineSource(source) {
+			return base.ErrUnknownProfileSource
+		}
+	}
+
+	checks := []struct {
+		counts map[string]int
+		kind   func(source string) bool
+	}{
+		{profile.QueueSlots, models.IsMachineSource},
+		{profile.Trees, models.IsTreeOrBushSource},
+		{profile.Bushes, models.IsTreeOrBushSource},
+		{profile.Animals, func(source string) bool { return models.AnimalSources[source] }},
+	}
+	for _, check := range checks {
+		for source, count := range check.counts {
+			if _, exists := known[source]; !exists || !check.kind(source) {
+				return base.ErrUnknownProfileSource
+			}
+			if count < 0 {
+				return base.ErrInvalidProfile
+			}
+		}
+	}
+
+	return nil
+}
+
+// Every owned machine is one production line, fields, trees, bushes and
+// animals run one line per unit the player has
+func playerStateFromProfile(profile models.PlayerProfile) models.PlayerState {
+	capacity := make(map[string]int)
+	for _, source := range profile.OwnedSources {
+		capacity[source]++
+	}
+	if profile.Fields > 0 {
+		capacity[models.SourceField] = profile.Fields
+	}
+	for _, counts := range []map[string]int{profile.Trees, profile.Bushes, profile.Animals} {
+		for source, count := range counts {
+			capacity[source] = count
+		}
+	}
+
+	return models.PlayerState{
+		Level:      profile.Level,
+		Capacity:   capacity,
+		QueueSlots: profile.QueueSlots,
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestProfileControllerStatus(t *testing.T) {
+	router := testRouter(t)
+
+	recorder := serve(router, http.MethodPost, "/profiles", `{"Name":"farm","Level":5,"OwnedSources":["Bakery"],"Fields":4,"Animals":{"Chicken":3}}`)
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", recorder.Code, recorder.Body)
+	}
+	var created models.PlayerProfile
+	if err := json.NewDecoder(recorder.Body).Decode(&created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	profile := "/profiles/" + created.ID.String()
+	unknown := "/profiles/6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+
+	runHandlerTests(t, router, []handlerTest{
+		{name: "get", method: http.MethodGet, path: profile, status: http.StatusOK},
+		{name: "get unknown", method: http.MethodGet, path: unknown, status: http.StatusNotFound},
+		{name: "get malformed id", method: http.MethodGet, path: "/profiles/farm", status: http.StatusNotFound},
+		{name: "create level zero", method: http.MethodPost, path: "/profiles", body: `{"Name":"farm","Level":0}`, status: http.StatusBadRequest},
+		{name: "create negative fields", method: http.MethodPost, path: "/profiles", body: `{"Level":5,"Fields":-1}`, status: http.StatusBadRequest},
+		{name: "create unknown source", method: http.MethodPost, path: "/profiles", body: `{"Level":5,"OwnedSources":["Smithy"]}`, status: http.StatusBadRequest},
+		{name: "create animal as machine", method: http.MethodPost, path: "/profiles", body: `{"Level":5,"OwnedSources":["Chicken"]}`, status: http.StatusBadRequest},
+		{name: "create malformed", method: http.MethodPost, path: "/profiles", body: `{"Level":`, status: http.StatusBadRequest},
+		{name: "update unknown", method: http.MethodPut, path: unknown, body: `{"Level":5}`, status: http.StatusNotFound},
+		{name: "strategy", method: http.MethodGet, path: profile + "/strategy", status: http.StatusOK},
+		{name: "unknown strategy", method: http.MethodGet, path: profile + "/strategy?strategy=nope", status: http.StatusBadRequest},
+		{name: "simulation", method: http.MethodGet, path: profile + "/strategy/simulation?timeline=0", status: http.StatusOK},
+		{name: "simulation negative timeline", method: http.MethodGet, path: profile + "/strategy/simulation?timeline=-1", status: http.StatusBadRequest},
+		{name: "delete unknown", method: http.MethodDelete, path: unknown, status: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, path: profile, status: http.StatusNoContent},
+		{name: "get deleted", method: http.MethodGet, path: profile, status: http.StatusNotFound},
+	})
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const profilesFileName = "profiles.json"
+
+type ProfileRepository struct {
+	fileManager *base.FileManager[models.PlayerProfileList]
+	profiles    models.PlayerProfileList
+	mu          sync.RWMutex
+}
+
+func NewProfileRepository(fileManager *base.FileManager[models.PlayerProfileList]) (*ProfileRepository, error) {
+	profiles := models.PlayerProfileList{}
+	if fileManager.Exists(profilesFileName) {
+		var err error
+		profiles, err = fileManager.Read(profilesFileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &ProfileRepository{
+		fileManager: fileManager,
+		profiles:    profiles,
+	}, nil
+}
+
+func (repo *ProfileRepository) GetAllProfiles() models.PlayerProfileList {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	return append(models.PlayerProfileList{}, repo.profiles...)
+}
+
+func (repo *ProfileRepository) GetProfileByID(id uuid.UUID) (*models.PlayerProfile, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+
+	for _, profile := range repo.profiles {
+		if profile.ID == id {
+			return &profile, nil
+		}
+	}
+
+	return nil, base.ErrNoProfileByIDFound
+}
+
+func (repo *ProfileRepository) CreateProfile(profile models.PlayerProfile) (*models.PlayerProfile, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	profile.ID = uuid.New()
+	repo.profiles = append(repo.profiles, profile)
+
+	if err := repo.fileManager.Write(profilesFileName, repo.profiles); err != nil {
+		repo.profiles = repo.profiles[:len(repo.profiles)-1]
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+func (repo *ProfileRepository) UpdateProfile(profile models.PlayerProfile) (*models.PlayerProfile, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existing := range repo.profiles {
+		if existing.ID != profile.ID {
+			continue
+		}
+
+		repo.profiles[i] = profile
+		if err := repo.fileManager.Write(profilesFileName, repo.profiles); err != nil {
+			repo.profiles[i] = existing
+			return nil, err
+		}
+		return &profile, nil
+	}
+
+	return nil, base.ErrNoProfileByIDFound
+}
+
+func (repo *ProfileRepository) DeleteProfile(id uuid.UUID) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i, existing := range repo.profiles {
+		if existing.ID != id {
+			continue
+		}
+
+		remaining := append(append(models.PlayerProfileList{}, repo.profiles[:i]...), repo.profiles[i+1:]...)
+		if err := repo.fileManager.Write(profilesFileName, remaining); err != nil {
+			return err
+		}
+		repo.profiles = remaining
+		return nil
+	}
+
+	return base.ErrNoProfileByIDFound
+}
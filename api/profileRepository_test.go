@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func testProfileRepository(t *testing.T, dir string) *ProfileRepository {
+	t.Helper()
+
+	fileManager, err := base.NewJsonFileManager[models.PlayerProfileList](dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo, err := NewProfileRepository(fileManager)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return repo
+}
+
+func TestProfileRepositoryPersists(t *testing.T) {
+	dir := t.TempDir()
+	repo := testProfileRepository(t, dir)
+
+	created, err := repo.CreateProfile(models.PlayerProfile{Name: "farm", Level: 12, Fields: 9})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID == uuid.Nil {
+		t.Fatal("created profile has no id")
+	}
+
+	created.Level = 13
+	if _, err := repo.UpdateProfile(*created); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A fresh repository reads what the first one wrote
+	reloaded, err := testProfileRepository(t, dir).GetProfileByID(created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reloaded.Name != "farm" || reloaded.Level != 13 || reloaded.Fields != 9 {
+		t.Errorf("reloaded = %+v, want the updated profile", reloaded)
+	}
+
+	if err := repo.DeleteProfile(created.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profiles := testProfileRepository(t, dir).GetAllProfiles(); len(profiles) != 0 {
+		t.Errorf("profiles after delete = %+v, want none", profiles)
+	}
+}
+
+func TestProfileRepositoryUnknownID(t *testing.T) {
+	repo := testProfileRepository(t, t.TempDir())
+	id := uuid.New()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{name: "get", run: func() error { _, err := repo.GetProfileByID(id); return err }},
+		{name: "update", run: func() error { _, err := repo.UpdateProfile(models.PlayerProfile{ID: id}); return err }},
+		{name: "delete", run: func() error { return repo.DeleteProfile(id) }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.run(); !errors.Is(err, base.ErrNoProfileByIDFound) {
+				t.Errorf("err = %v, want %v", err, base.ErrNoProfileByIDFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// The greedy strategies plan one good per source, so a second copy of a
+// machine only shows up in strategies that count production lines
+const purchaseDefaultStrategy = "lp"
+
+// Compares the plan for a farm with and without one more copy of a source to
+// tell how long a purchase takes to pay for itself
+type PurchaseEvaluator struct {
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
+}
+
+func NewPurchaseEvaluator(repo *GoodsRepository, strategies *StrategyRegistry) *PurchaseEvaluator {
+	return &PurchaseEvaluator{
+		repo:       repo,
+		strategies: strategies,
+	}
+}
+
+func (e *PurchaseEvaluator) Evaluate(ctx context.Context, state models.PlayerState, request models.PurchaseRequest) (models.PurchaseEvaluation, error) {
+	if request.Cost < 0 {
+		return models.PurchaseEvaluation{}, base.ErrInvalidQuantity
+	}
+	if _, exists := groupGoodsBySource(e.repo.GetGoodsByLevel(state.Level))[request.Source]; !exists {
+		return models.PurchaseEvaluation{}, base.ErrNoSourceByNameFound
+	}
+
+	strategyName := request.Strategy
+	if strategyName == "" {
+		strategyName = purchaseDefaultStrategy
+	}
+	state.Horizon = time.Duration(request.Hours) * time.Hour
+
+	before, err := runStrategy(ctx, e.strategies, strategyName, state)
+	if err != nil {
+		return models.PurchaseEvaluation{}, err
+	}
+
+	owned := sourceCapacity(state, request.Source)
+	after, err := runStrategy(ctx, e.strategies, strategyName, withCapacity(e.repo, state, request.Source, owned+1))
+	if err != nil {
+		return models.PurchaseEvaluation{}, err
+	}
+
+	evaluation := models.PurchaseEvaluation{
+		Source:            request.Source,
+		Cost:              request.Cost,
+		Owned:             owned,
+		Strategy:          strategyName,
+		CoinsPerDayBefore: before.CoinsPerHour * hoursPerDay,
+		CoinsPerDayAfter:  after.CoinsPerHour * hoursPerDay,
+		NewGoods:          []string{},
+	}
+	evaluation.ExtraCoinsPerDay = evaluation.CoinsPerDayAfter - evaluation.CoinsPerDayBefore
+
+	if evaluation.ExtraCoinsPerDay > 0 {
+		evaluation.PaysBack = true
+		evaluation.PaybackDays = float64(request.Cost) / evaluation.ExtraCoinsPerDay
+	}
+
+	if added, _ := planDifference(before, after); added != nil {
+		evaluation.NewGoods = added
+	}
+
+	return evaluation, nil
+}
+
+// Copy of the state with the number of lines of one source replaced. A state
+// without capacities has the typical farm of its level.
+func withCapacity(repo *GoodsRepository, state models.PlayerState, source string, lines int) models.PlayerState {
+	capacity := make(map[string]int)
+	if state.Capacity == nil {
+		for unlocked := range groupGoodsBySource(repo.GetGoodsByLevel(state.Level)) {
+			capacity[unlocked] = models.DefaultSourceCount(state.Level, unlocked)
+		}
+	}
+	for name, count := range state.Capacity {
+		capacity[name] = count
+	}
+	capacity[source] = lines
+
+	state.Capacity = capacity
+	return state
+}
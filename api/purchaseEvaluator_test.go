@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestPurchaseEvaluatorRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   int
+		request models.PurchaseRequest
+		want    error
+	}{
+		{name: "negative cost", level: 5, request: models.PurchaseRequest{Source: "Bakery", Cost: -1}, want: base.ErrInvalidQuantity},
+		{name: "unknown source", level: 5, request: models.PurchaseRequest{Source: "Sugar Mill", Cost: 100}, want: base.ErrNoSourceByNameFound},
+		{name: "locked source", level: 1, request: models.PurchaseRequest{Source: "Bakery", Cost: 100}, want: base.ErrNoSourceByNameFound},
+		{name: "unknown strategy", level: 5, request: models.PurchaseRequest{Source: "Bakery", Cost: 100, Strategy: "nope"}, want: base.ErrUnknownStrategy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := testRepository()
+			evaluator := NewPurchaseEvaluator(repo, NewDefaultStrategyRegistry(repo))
+
+			_, err := evaluator.Evaluate(context.Background(), models.PlayerState{Level: test.level}, test.request)
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseEvaluatorFirstMachine(t *testing.T) {
+	// Without a bakery the fields can only sell their crops. Replanted every
+	// two minutes they grow more wheat than a bakery can bake, and bread
+	// earns more than the wheat it takes.
+	state := models.PlayerState{
+		Level:         5,
+		VisitInterval: 2 * time.Minute,
+		Capacity:      map[string]int{models.SourceField: 4, "Bakery": 0},
+	}
+	repo := testRepository()
+	evaluator := NewPurchaseEvaluator(repo, NewDefaultStrategyRegistry(repo))
+
+	evaluation, err := evaluator.Evaluate(context.Background(), state, models.PurchaseRequest{Source: "Bakery", Cost: 1000, Hours: 24})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if evaluation.Owned != 0 {
+		t.Errorf("owned = %d, want 0", evaluation.Owned)
+	}
+	if !evaluation.PaysBack || evaluation.ExtraCoinsPerDay <= 0 {
+		t.Fatalf("extra coins per day = %v, want the bakery to pay back", evaluation.ExtraCoinsPerDay)
+	}
+	if want := 1000 / evaluation.ExtraCoinsPerDay; evaluation.PaybackDays != want {
+		t.Errorf("payback days = %v, want %v", evaluation.PaybackDays, want)
+	}
+	if len(evaluation.NewGoods) == 0 {
+		t.Error("no new goods, want bakery goods in the plan")
+	}
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Read the planning parameters shared by all strategy endpoints
+func parsePlayerState(r *http.Request) (models.PlayerState, error) {
+	var state models.PlayerState
+	var err error
+
+	state.Level, err = strconv.Atoi(r.PathValue("level"))
+	if err != nil {
+		return state, base.ErrInvalidStringParse
+	}
+
+	state.XPToTarget, err = parseOptionalInt(r, "xp_to_target")
+	if err != nil {
+		return state, err
+	}
+
+	hours, err := parseOptionalInt(r, "hours")
+	if err != nil {
+		return state, err
+	}
+	state.Horizon = time.Duration(hours) * time.Hour
+
+	state.VisitInterval, err = parseOptionalDuration(r, "visit", 0)
+	if err != nil {
+		return state, err
+	}
+	state.Explain = r.URL.Query().Get("explain") == "true"
+
+	return state, nil
+}
+
+func parseOptionalInt(r *http.Request, key string) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, base.ErrInvalidStringParse
+	}
+	return parsed, nil
+}
+
+func parseOptionalFloat(r *http.Request, key string) (float64, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, base.ErrInvalidStringParse
+	}
+	return parsed, nil
+}
+
+// Objective weights are optional, nil means the request did not name any
+func parseObjectiveWeights(r *http.Request) (*models.ObjectiveWeights, error) {
+	query := r.URL.Query()
+	if !query.Has("coins") && !query.Has("xp") && !query.Has("time") {
+		return nil, nil
+	}
+
+	var weights models.ObjectiveWeights
+	var err error
+	if weights.Coins, err = parseOptionalFloat(r, "coins"); err != nil {
+		return nil, err
+	}
+	if weights.XP, err = parseOptionalFloat(r, "xp"); err != nil {
+		return nil, err
+	}
+	if weights.Time, err = parseOptionalFloat(r, "time"); err != nil {
+		return nil, err
+	}
+
+	if weights.Coins < 0 || weights.XP < 0 || weights.Time < 0 || weights.Coins+weights.XP+weights.Time == 0 {
+		return nil, base.ErrInvalidWeights
+	}
+	return &weights, nil
+}
+
+// Durations use Go notation such as 45m or 8h
+func parseOptionalDuration(r *http.Request, key string, fallback time.Duration) (time.Duration, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return 0, base.ErrInvalidTimeString
+	}
+	return parsed, nil
+}
+
+// Events of a simulation timeline, a fixed number unless the request asks
+// for all of them or a count of its own. Zero leaves the timeline out.
+func parseTimelineLimit(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("timeline")
+	switch value {
+	case "":
+		return defaultTimelineEvents, nil
+	case "all":
+		return math.MaxInt, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, base.ErrInvalidStringParse
+	}
+	return parsed, nil
+}
+
+// Session length and offline gap of a session plan, together no longer than
+// the planner handles
+func parseSessionWindow(r *http.Request) (time.Duration, time.Duration, error) {
+	session, err := parseOptionalDuration(r, "session", defaultSessionLength)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	offline, err := parseOptionalDuration(r, "offline", defaultOfflineGap)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if session+offline > maxSessionCycle {
+		return 0, 0, base.ErrSessionTooLong
+	}
+	return session, offline, nil
+}
+
+// The strategy query parameter, or the strategy ranking by the older metric
+// parameter when only that one is given
+func parseStrategyName(r *http.Request) (string, error) {
+	strategyName := r.URL.Query().Get("strategy")
+
+	value := r.URL.Query().Get("metric")
+	if value == "" {
+		return strategyName, nil
+	}
+
+	metric, err := ParseRankingMetric(value)
+	if err != nil {
+		return "", err
+	}
+	if strategyName != "" && strategyName != metricStrategies[metric] {
+		return "", base.ErrStrategyMetricConflict
+	}
+	return metricStrategies[metric], nil
+}
+
+// Errors caused by the request body are the client's fault
+func inputErrorStatus(err error) int {
+	if errors.Is(err, base.ErrNoGoodByNameFound) || errors.Is(err, base.ErrInvalidQuantity) ||
+		errors.Is(err, base.ErrInvalidInventory) || errors.Is(err, base.ErrInvalidCapacity) || errors.Is(err, base.ErrDeadlinePassed) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func strategyErrorStatus(err error) int {
+	if errors.Is(err, base.ErrUnknownStrategy) || errors.Is(err, base.ErrInvalidLevelRange) || errors.Is(err, base.ErrMissingLevelXP) ||
+		errors.Is(err, base.ErrStrategyNotPerturbable) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Planners that run a strategy on top of their own input can fail on either
+func planningErrorStatus(err error) int {
+	if errors.Is(err, base.ErrNoSourceByNameFound) || inputErrorStatus(err) == http.StatusBadRequest {
+		return http.StatusBadRequest
+	}
+	return strategyErrorStatus(err)
+}
+
+func runStrategy(ctx context.Context, strategies *StrategyRegistry, strategyName string, state models.PlayerState) (models.Plan, error) {
+	if strategyName == "" {
+		strategyName = defaultStrategy
+	}
+
+	strategy, err := strategies.Get(strategyName)
+	if err != nil {
+		return models.Plan{}, err
+	}
+
+	plan, err := strategy.Plan(ctx, state)
+	if err != nil {
+		return models.Plan{}, err
+	}
+	plan.Strategy = strategyName
+
+	if !state.Explain {
+		plan.Decisions = nil
+	}
+
+	return plan, nil
+}
+
+func parseProfileID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, base.ErrNoProfileByIDFound
+	}
+	return id, nil
+}
@@ -0,0 +1,145 @@
+package api
+
+import (
+	"context"
+	"sort"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const (
+	// Longest level range a single roadmap covers
+	maxRoadmapLevels  = 100
+	roadmapXPStrategy = "xp"
+)
+
+// Walks a range of levels and records what unlocks at each one, how the plan
+// of the chosen strategy reacts and how long the level takes with the xp strategy
+type RoadmapPlanner struct {
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
+}
+
+func NewRoadmapPlanner(repo *GoodsRepository, strategies *StrategyRegistry) *RoadmapPlanner {
+	return &RoadmapPlanner{
+		repo:       repo,
+		strategies: strategies,
+	}
+}
+
+func (p *RoadmapPlanner) Plan(ctx context.Context, request models.RoadmapRequest) (models.LevelRoadmap, error) {
+	if request.From < 1 || request.To < request.From || request.To-request.From > maxRoadmapLevels {
+		return models.LevelRoadmap{}, base.ErrInvalidLevelRange
+	}
+
+	// Hours are only estimated with experience for every level of the range,
+	// a total that silently skips levels would be too low
+	estimate := len(request.XPToNextLevel) > 0
+	if estimate {
+		if _, err := xpBetweenLevels(request.XPToNextLevel, request.From, request.To); err != nil {
+			return models.LevelRoadmap{}, err
+		}
+	}
+
+	roadmap := models.LevelRoadmap{
+		From:  request.From,
+		To:    request.To,
+		Steps: []models.RoadmapStep{},
+	}
+
+	previous, err := runStrategy(ctx, p.strategies, request.Strategy, models.PlayerState{Level: request.From - 1})
+	if err != nil {
+		return models.LevelRoadmap{}, err
+	}
+	roadmap.Strategy = previous.Strategy
+
+	unlocks := p.unlocksByLevel()
+
+	for level := request.From; level <= request.To; level++ {
+		state := models.PlayerState{Level: level}
+
+		plan, err := runStrategy(ctx, p.strategies, request.Strategy, state)
+		if err != nil {
+			return models.LevelRoadmap{}, err
+		}
+
+		step := unlocks[level]
+		step.Level = level
+		step.CoinsPerHour = plan.CoinsPerHour
+		step.AddedToPlan, step.RemovedFromPlan = planDifference(previous, plan)
+
+		// Reaching the last level is the goal, so it needs no estimate
+		state.XPToTarget = request.XPToNextLevel[level]
+		if estimate && level < request.To {
+			xpPlan, err := runStrategy(ctx, p.strategies, roadmapXPStrategy, state)
+			if err != nil {
+				return models.LevelRoadmap{}, err
+			}
+			step.XPPerHour = xpPlan.XPPerHour
+			step.HoursToNextLevel = xpPlan.HoursToTarget
+			roadmap.TotalHours += xpPlan.HoursToTarget
+		}
+
+		roadmap.Steps = append(roadmap.Steps, step)
+		previous = plan
+	}
+
+	return roadmap, nil
+}
+
+// Goods by the level they unlock at. A source unlocks together with the
+// first good it can make.
+func (p *RoadmapPlanner) unlocksByLevel() map[int]models.RoadmapStep {
+	steps := make(map[int]models.RoadmapStep)
+	firstLevel := make(map[string]int)
+
+	for _, good := range p.repo.GetAllGoods() {
+		step := steps[good.RequiredLevel]
+		step.UnlockedGoods = append(step.UnlockedGoods, good.Name)
+		steps[good.RequiredLevel] = step
+
+		if level, seen := firstLevel[good.Source]; !seen || good.RequiredLevel < level {
+			firstLevel[good.Source] = good.RequiredLevel
+		}
+	}
+
+	for source, level := range firstLevel {
+		step := steps[level]
+		step.UnlockedSources = append(step.UnlockedSources, source)
+		steps[level] = step
+	}
+
+	for level, step := range steps {
+		sort.Strings(step.UnlockedGoods)
+		sort.Strings(step.UnlockedSources)
+		steps[level] = step
+	}
+
+	return steps
+}
+
+func planDifference(previous, current models.Plan) (added, removed []string) {
+	before := make(map[string]bool)
+	for _, good := range previous.Goods {
+		before[good.Name] = true
+	}
+
+	after := make(map[string]bool)
+	for _, good := range current.Goods {
+		after[good.Name] = true
+		if !before[good.Name] {
+			added = append(added, good.Name)
+		}
+	}
+
+	for _, good := range previous.Goods {
+		if !after[good.Name] {
+			removed = append(removed, good.Name)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
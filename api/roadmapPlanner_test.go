@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func testRoadmapPlanner() *RoadmapPlanner {
+	repo := testRepository()
+	return NewRoadmapPlanner(repo, NewDefaultStrategyRegistry(repo))
+}
+
+func TestRoadmapPlannerRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		request models.RoadmapRequest
+		want    error
+	}{
+		{name: "level zero", request: models.RoadmapRequest{From: 0, To: 3}, want: base.ErrInvalidLevelRange},
+		{name: "reversed", request: models.RoadmapRequest{From: 5, To: 3}, want: base.ErrInvalidLevelRange},
+		{name: "too long", request: models.RoadmapRequest{From: 1, To: 2 + maxRoadmapLevels}, want: base.ErrInvalidLevelRange},
+		{name: "gap in experience", request: models.RoadmapRequest{From: 1, To: 4, XPToNextLevel: map[int]int{1: 10, 3: 30}}, want: base.ErrMissingLevelXP},
+		{name: "unknown strategy", request: models.RoadmapRequest{From: 1, To: 3, Strategy: "nope"}, want: base.ErrUnknownStrategy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := testRoadmapPlanner().Plan(context.Background(), test.request)
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestRoadmapPlannerSteps(t *testing.T) {
+	roadmap, err := testRoadmapPlanner().Plan(context.Background(), models.RoadmapRequest{
+		From:          1,
+		To:            5,
+		XPToNextLevel: map[int]int{1: 10, 2: 20, 3: 30, 4: 40},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(roadmap.Steps) != 5 {
+		t.Fatalf("steps = %d, want one per level", len(roadmap.Steps))
+	}
+	if step := roadmap.Steps[1]; !slices.Contains(step.UnlockedGoods, "Bread") || !slices.Contains(step.UnlockedSources, "Bakery") {
+		t.Errorf("level 2 unlocks %v and %v, want bread and the bakery", step.UnlockedGoods, step.UnlockedSources)
+	}
+	if step := roadmap.Steps[4]; !slices.Contains(step.UnlockedGoods, "Corn bread") || step.HoursToNextLevel != 0 {
+		t.Errorf("level 5 = %+v, want corn bread and no estimate past the goal", step)
+	}
+
+	total := 0.0
+	for _, step := range roadmap.Steps {
+		total += step.HoursToNextLevel
+	}
+	if total <= 0 || math.Abs(roadmap.TotalHours-total) > 1e-9 {
+		t.Errorf("total hours = %v, want the sum %v of the steps", roadmap.TotalHours, total)
+	}
+}
@@ -0,0 +1,325 @@
+package api
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const (
+	defaultSensitivityPercent = 10.0
+	// Best goods outside the plan per planned source that get perturbed, the
+	// ones further down would need more than a small change to enter it
+	sensitivityContenders = 2
+	// Every scenario is a full strategy run, an exact one takes more than a
+	// tenth of a second per core at higher levels. No new scenario is started
+	// once the budget is used up.
+	maxSensitivityScenarios = 64
+	sensitivityTimeBudget   = 10 * time.Second
+)
+
+// Checks how much a plan depends on the exact wiki values. The planned goods,
+// the closest contenders of every planned source and the ingredients of the
+// planned goods have their price and production time moved up and down by a
+// percentage, one at a time, and the strategy is run again on the changed
+// data. Scenarios run in that order, so once the limit or the time budget is
+// reached it is the least relevant goods that are left out.
+type SensitivityAnalyzer struct {
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
+	budget     time.Duration
+}
+
+func NewSensitivityAnalyzer(repo *GoodsRepository, strategies *StrategyRegistry) *SensitivityAnalyzer {
+	return &SensitivityAnalyzer{
+		repo:       repo,
+		strategies: strategies,
+		budget:     sensitivityTimeBudget,
+	}
+}
+
+func (a *SensitivityAnalyzer) Analyze(ctx context.Context, strategyName string, state models.PlayerState, percent float64) (models.SensitivityReport, error) {
+	state.Explain = false
+
+	if strategyName == "" {
+		strategyName = defaultStrategy
+	}
+	strategy, err := a.strategies.Get(strategyName)
+	if err != nil {
+		return models.SensitivityReport{}, err
+	}
+	bound, ok := strategy.(GoodsBoundStrategy)
+	if !ok {
+		return models.SensitivityReport{}, base.ErrStrategyNotPerturbable
+	}
+
+	baseline, err := strategy.Plan(ctx, state)
+	if err != nil {
+		return models.SensitivityReport{}, err
+	}
+
+	report := models.SensitivityReport{
+		Strategy:        strategyName,
+		Level:           state.Level,
+		Percent:         percent,
+		MinCoinsPerHour: baseline.CoinsPerHour,
+		MaxCoinsPerHour: baseline.CoinsPerHour,
+	}
+
+	recommended := make(map[string]bool)
+	for _, good := range baseline.Goods {
+		recommended[good.Name] = true
+	}
+
+	perturbations := a.perturbations(a.scenarioGoods(state, baseline), percent)
+	limit := min(len(perturbations), maxSensitivityScenarios)
+	plans, err := a.runScenarios(ctx, bound, state, perturbations[:limit])
+	if err != nil {
+		return models.SensitivityReport{}, err
+	}
+	report.Skipped = len(perturbations) - len(plans)
+	report.OutOfTime = len(plans) < limit
+
+	// A good is only tested once its own price and time were both moved up
+	// and down, any of the perturbations left out could have dropped it
+	untested := make(map[string]bool)
+	for _, perturbation := range perturbations[len(plans):] {
+		untested[perturbation.Good] = true
+	}
+
+	droppedBy := make(map[string][]models.Perturbation)
+	addedBy := make(map[string][]models.Perturbation)
+	sources := make(map[string]string)
+
+	for i, plan := range plans {
+		perturbation := perturbations[i]
+
+		report.Scenarios++
+		report.MinCoinsPerHour = math.Min(report.MinCoinsPerHour, plan.CoinsPerHour)
+		report.MaxCoinsPerHour = math.Max(report.MaxCoinsPerHour, plan.CoinsPerHour)
+
+		planned := make(map[string]bool)
+		for _, good := range plan.Goods {
+			planned[good.Name] = true
+			if !recommended[good.Name] {
+				addedBy[good.Name] = append(addedBy[good.Name], perturbation)
+				sources[good.Name] = good.Source
+			}
+		}
+		for name := range recommended {
+			if !planned[name] {
+				droppedBy[name] = append(droppedBy[name], perturbation)
+			}
+		}
+	}
+
+	for _, good := range baseline.Goods {
+		tested := !untested[good.Name]
+		report.Recommendations = append(report.Recommendations, models.RecommendationStability{
+			Good:      good.Name,
+			Source:    good.Source,
+			Tested:    tested,
+			Stable:    tested && len(droppedBy[good.Name]) == 0,
+			DroppedBy: droppedBy[good.Name],
+		})
+	}
+
+	report.Entrants = []models.PlanEntrant{}
+	for name, perturbations := range addedBy {
+		report.Entrants = append(report.Entrants, models.PlanEntrant{
+			Good:    name,
+			Source:  sources[name],
+			AddedBy: perturbations,
+		})
+	}
+	// Goods that enter under many perturbations are the closest contenders
+	sort.Slice(report.Entrants, func(i, j int) bool {
+		if len(report.Entrants[i].AddedBy) != len(report.Entrants[j].AddedBy) {
+			return len(report.Entrants[i].AddedBy) > len(report.Entrants[j].AddedBy)
+		}
+		return report.Entrants[i].Good < report.Entrants[j].Good
+	})
+
+	return report, nil
+}
+
+// Goods to perturb, most relevant first: the planned goods, the best
+// unplanned goods of the planned sources that a small change could swap in,
+// then everything that goes into the planned goods
+func (a *SensitivityAnalyzer) scenarioGoods(state models.PlayerState, baseline models.Plan) models.HayDayGoodList {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	for _, good := range a.repo.GetAllGoods() {
+		goodsMap[good.ID] = good
+	}
+
+	selected := make(map[uuid.UUID]bool)
+	var result models.HayDayGoodList
+	add := func(good models.HayDayGood) bool {
+		if selected[good.ID] {
+			return false
+		}
+		selected[good.ID] = true
+		result = append(result, good)
+		return true
+	}
+
+	plannedSources := make(map[string]bool)
+	for _, planned := range baseline.Goods {
+		add(planned.HayDayGood)
+		plannedSources[planned.Source] = true
+	}
+
+	optimizer := NewOptimizer(a.repo.GetAllGoods(), MetricNetProfit).withLifecycles(state.Lifecycles)
+	bySource := groupGoodsBySource(availableGoods(a.repo, state))
+	sources := make([]string, 0, len(bySource))
+	for source := range bySource {
+		if plannedSources[source] {
+			sources = append(sources, source)
+		}
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		goods := bySource[source]
+		sort.SliceStable(goods, func(i, j int) bool {
+			return optimizer.netCoinsPerHour(goods[i]) > optimizer.netCoinsPerHour(goods[j])
+		})
+		contenders := 0
+		for _, good := range goods {
+			if contenders == sensitivityContenders {
+				break
+			}
+			if add(good) {
+				contenders++
+			}
+		}
+	}
+
+	// Breadth first, so direct ingredients come before deeper ones
+	queue := make([]models.HayDayGood, 0, len(baseline.Goods))
+	for _, planned := range baseline.Goods {
+		queue = append(queue, planned.HayDayGood)
+	}
+	for len(queue) > 0 {
+		good := queue[0]
+		queue = queue[1:]
+		for _, ingredient := range good.Ingredients {
+			ingredientGood, found := goodsMap[ingredient.ProductID]
+			if found && add(ingredientGood) {
+				queue = append(queue, ingredientGood)
+			}
+		}
+	}
+
+	return result
+}
+
+// Raise and lower the price and production time of every given good.
+// Goods without a known production time only get their price changed.
+func (a *SensitivityAnalyzer) perturbations(goods models.HayDayGoodList, percent float64) []models.Perturbation {
+	var perturbations []models.Perturbation
+	for _, good := range goods {
+		for _, sign := range []float64{1, -1} {
+			perturbations = append(perturbations, models.Perturbation{
+				Good:    good.Name,
+				Field:   models.FieldMaxPrice,
+				Percent: sign * percent,
+			})
+			if good.ProductionTime > 0 {
+				perturbations = append(perturbations, models.Perturbation{
+					Good:    good.Name,
+					Field:   models.FieldProductionTime,
+					Percent: sign * percent,
+				})
+			}
+		}
+	}
+	return perturbations
+}
+
+// Scenarios are independent, so they are planned on all cores. The plans
+// come back in the order of the perturbations, cut off at the first one that
+// was not started within the time budget.
+func (a *SensitivityAnalyzer) runScenarios(ctx context.Context, strategy GoodsBoundStrategy, state models.PlayerState, perturbations []models.Perturbation) ([]models.Plan, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	deadline := time.Now().Add(a.budget)
+	plans := make([]models.Plan, len(perturbations))
+	indices := make(chan int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	for range min(runtime.NumCPU(), len(perturbations)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				repo := newGoodsRepositoryFromList(a.perturb(perturbations[i]))
+				plan, err := strategy.WithGoods(repo).Plan(ctx, state)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				plans[i] = plan
+			}
+		}()
+	}
+
+	started := 0
+feed:
+	for i := range perturbations {
+		if !time.Now().Before(deadline) {
+			break
+		}
+		select {
+		case indices <- i:
+			started++
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return plans[:started], nil
+}
+
+// Copy of all goods with the perturbation applied
+func (a *SensitivityAnalyzer) perturb(perturbation models.Perturbation) models.HayDayGoodList {
+	factor := 1 + perturbation.Percent/100
+
+	goods := make(models.HayDayGoodList, len(a.repo.GetAllGoods()))
+	copy(goods, a.repo.GetAllGoods())
+
+	for i, good := range goods {
+		if good.Name != perturbation.Good {
+			continue
+		}
+		switch perturbation.Field {
+		case models.FieldMaxPrice:
+			goods[i].MaxPrice = int(math.Round(float64(good.MaxPrice) * factor))
+		case models.FieldProductionTime:
+			goods[i].ProductionTime = time.Duration(float64(good.ProductionTime) * factor)
+		}
+	}
+	return goods
+}
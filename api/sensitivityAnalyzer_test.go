@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Plans without being able to run on other goods data
+type fixedStrategy struct{}
+
+func (fixedStrategy) Plan(ctx context.Context, state models.PlayerState) (models.Plan, error) {
+	return models.Plan{}, nil
+}
+
+func testSensitivityAnalyzer() *SensitivityAnalyzer {
+	repo := testRepository()
+	strategies := NewDefaultStrategyRegistry(repo)
+	strategies.Register("fixed", fixedStrategy{})
+	return NewSensitivityAnalyzer(repo, strategies)
+}
+
+func TestSensitivityReportsResolvedStrategy(t *testing.T) {
+	report, err := testSensitivityAnalyzer().Analyze(context.Background(), "", models.PlayerState{Level: 10}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Strategy != defaultStrategy {
+		t.Errorf("strategy = %q, want %q", report.Strategy, defaultStrategy)
+	}
+	if report.Scenarios == 0 || report.OutOfTime {
+		t.Errorf("scenarios = %d, out of time = %v, want every scenario run", report.Scenarios, report.OutOfTime)
+	}
+	for _, recommendation := range report.Recommendations {
+		if !recommendation.Tested {
+			t.Errorf("%s not tested", recommendation.Good)
+		}
+	}
+}
+
+func TestSensitivityOutOfTimeIsNotStable(t *testing.T) {
+	analyzer := testSensitivityAnalyzer()
+	analyzer.budget = 0
+
+	report, err := analyzer.Analyze(context.Background(), "lp", models.PlayerState{Level: 10}, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.Scenarios != 0 || !report.OutOfTime || report.Skipped == 0 {
+		t.Errorf("scenarios = %d, skipped = %d, out of time = %v, want none run", report.Scenarios, report.Skipped, report.OutOfTime)
+	}
+	if len(report.Recommendations) == 0 {
+		t.Fatal("no recommendations")
+	}
+	for _, recommendation := range report.Recommendations {
+		if recommendation.Tested || recommendation.Stable {
+			t.Errorf("%s tested = %v, stable = %v, want neither", recommendation.Good, recommendation.Tested, recommendation.Stable)
+		}
+	}
+}
+
+func TestSensitivityRejectsStrategies(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     error
+	}{
+		{strategy: "nope", want: base.ErrUnknownStrategy},
+		{strategy: "fixed", want: base.ErrStrategyNotPerturbable},
+	}
+
+	for _, test := range tests {
+		t.Run(test.strategy, func(t *testing.T) {
+			_, err := testSensitivityAnalyzer().Analyze(context.Background(), test.strategy, models.PlayerState{Level: 10}, 10)
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
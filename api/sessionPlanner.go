@@ -0,0 +1,363 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const (
+	defaultQueueSlots    = 2
+	defaultSessionLength = 30 * time.Minute
+	defaultOfflineGap    = 8 * time.Hour
+	// Longest session plus offline gap planned in one go, the knapsack that
+	// fills the queues grows with every minute of it
+	maxSessionCycle = 48 * time.Hour
+)
+
+// Builds a to-do list for a play session followed by an offline gap: short jobs
+// that are collected while playing, then long ones that finish while away.
+// Every source first makes what the best goods of the other sources consume,
+// and a job is only started once its ingredients are in storage.
+type SessionPlanner struct {
+	optimizer   *Optimizer
+	expander    *BOMExpander
+	goodsMap    map[uuid.UUID]models.HayDayGood
+	goodsByName map[string]models.HayDayGood
+}
+
+type sessionLine struct {
+	source string
+	index  int
+	freeAt time.Duration
+}
+
+type sessionStock struct {
+	ready    map[uuid.UUID]int
+	incoming map[time.Duration]map[uuid.UUID]int
+}
+
+func NewSessionPlanner(allGoods models.HayDayGoodList) *SessionPlanner {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	goodsByName := make(map[string]models.HayDayGood)
+	for _, good := range allGoods {
+		goodsMap[good.ID] = good
+		goodsByName[good.Name] = good
+	}
+
+	return &SessionPlanner{
+		optimizer:   NewOptimizer(allGoods, MetricNetProfit),
+		expander:    NewBOMExpander(allGoods),
+		goodsMap:    goodsMap,
+		goodsByName: goodsByName,
+	}
+}
+
+func (p *SessionPlanner) Plan(availableGoods models.HayDayGoodList, state models.PlayerState, session, offline time.Duration) models.SessionPlan {
+	plan := models.SessionPlan{
+		Session:    session,
+		OfflineGap: offline,
+		Tasks:      []models.SessionTask{},
+	}
+
+	candidates := make(map[string]models.HayDayGoodList)
+	for source, goods := range groupGoodsBySource(availableGoods) {
+		candidates[source] = p.profitableGoods(goods)
+	}
+
+	stock := sessionStock{
+		ready:    stockByID(p.goodsByName, state.Inventory),
+		incoming: make(map[time.Duration]map[uuid.UUID]int),
+	}
+	demand := p.ingredientDemand(candidates, state, session, offline, stock.ready)
+
+	var lines []*sessionLine
+	for source, goods := range groupGoodsBySource(availableGoods) {
+		if len(goods) == 0 {
+			continue
+		}
+		for index := range sourceCapacity(state, source) {
+			lines = append(lines, &sessionLine{source: source, index: index})
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].source != lines[j].source {
+			return lines[i].source < lines[j].source
+		}
+		return lines[i].index < lines[j].index
+	})
+
+	// While playing, free lines take work whenever something is collected
+	for now := time.Duration(0); now < session; {
+		stock.collect(now)
+		for _, line := range lines {
+			if line.freeAt > now {
+				continue
+			}
+			good, found := p.sessionJob(candidates[line.source], demand, stock.ready, session-now)
+			if !found {
+				continue
+			}
+			p.start(&plan, line, good, now, &stock, demand)
+		}
+
+		next, pending := stock.nextArrival(now, session)
+		for _, line := range lines {
+			if line.freeAt > now && line.freeAt < session && (!pending || line.freeAt < next) {
+				next, pending = line.freeAt, true
+			}
+		}
+		if !pending {
+			break
+		}
+		now = next
+	}
+
+	// The last jobs fill the queue before leaving and have to be ready on
+	// return, they can only use what is in storage by then
+	stock.collect(session)
+	for _, line := range lines {
+		start := max(line.freeAt, session)
+		makeable := p.makeable(candidates[line.source], stock.ready)
+		for _, good := range p.fillGap(makeable, session+offline-start, queueSlots(state, line.source)) {
+			if !p.inStock(good, stock.ready) {
+				continue
+			}
+			p.start(&plan, line, good, max(line.freeAt, session), &stock, demand)
+		}
+	}
+
+	sort.Slice(plan.Tasks, func(i, j int) bool {
+		if plan.Tasks[i].StartAt != plan.Tasks[j].StartAt {
+			return plan.Tasks[i].StartAt < plan.Tasks[j].StartAt
+		}
+		if plan.Tasks[i].Source != plan.Tasks[j].Source {
+			return plan.Tasks[i].Source < plan.Tasks[j].Source
+		}
+		return plan.Tasks[i].Line < plan.Tasks[j].Line
+	})
+
+	return plan
+}
+
+// Ingredients the best good of every source consumes, beyond what storage
+// already holds. A line makes the good back to back while playing and fills
+// its queue with it before leaving.
+func (p *SessionPlanner) ingredientDemand(candidates map[string]models.HayDayGoodList, state models.PlayerState, session, offline time.Duration, inventory map[uuid.UUID]int) map[uuid.UUID]int {
+	remaining := make(map[uuid.UUID]int)
+	for id, amount := range inventory {
+		remaining[id] = amount
+	}
+
+	needs := make(map[uuid.UUID]*models.IngredientNeed)
+	for source, goods := range candidates {
+		good, found := p.bestRateWithin(goods, session+offline)
+		if !found || isBaseProduct(good) {
+			continue
+		}
+		runs := sourceCapacity(state, source) * (int(session/good.ProductionTime) + queueSlots(state, source))
+		p.expander.ExpandNeeds(good, runs*models.BatchYield(source), remaining, needs)
+	}
+
+	demand := make(map[uuid.UUID]int)
+	for id, need := range needs {
+		if need.ToProduce > 0 {
+			demand[id] = need.ToProduce
+		}
+	}
+	return demand
+}
+
+// Ingredients other lines are waiting for come first, then whatever earns
+// the most per hour. Either has to be ready before the session ends.
+func (p *SessionPlanner) sessionJob(candidates models.HayDayGoodList, demand, ready map[uuid.UUID]int, remaining time.Duration) (models.HayDayGood, bool) {
+	var best models.HayDayGood
+	bestDemand := 0
+	for _, good := range p.withDemanded(candidates, demand) {
+		if demand[good.ID] > bestDemand && good.ProductionTime <= remaining && p.inStock(good, ready) {
+			best, bestDemand = good, demand[good.ID]
+		}
+	}
+	if bestDemand > 0 {
+		return best, true
+	}
+
+	return p.bestRateWithin(p.makeable(candidates, ready), remaining)
+}
+
+func (p *SessionPlanner) start(plan *models.SessionPlan, line *sessionLine, good models.HayDayGood, start time.Duration, stock *sessionStock, demand map[uuid.UUID]int) {
+	for _, ingredient := range good.Ingredients {
+		stock.ready[ingredient.ProductID] -= ingredient.Amount
+	}
+
+	yield := models.BatchYield(good.Source)
+	ready := start + good.ProductionTime
+	if stock.incoming[ready] == nil {
+		stock.incoming[ready] = make(map[uuid.UUID]int)
+	}
+	stock.incoming[ready][good.ID] += yield
+	demand[good.ID] = max(demand[good.ID]-yield, 0)
+	line.freeAt = ready
+
+	plan.Tasks = append(plan.Tasks, models.SessionTask{
+		Source:          line.source,
+		Line:            line.index,
+		Good:            good.Name,
+		StartAt:         start,
+		ReadyAt:         ready,
+		CollectAfterGap: ready > plan.Session,
+	})
+	plan.Coins += p.optimizer.netValue(good) * yield
+	plan.XP += good.GainedXP
+}
+
+// Move everything finished by now into storage
+func (s *sessionStock) collect(now time.Duration) {
+	for at, goods := range s.incoming {
+		if at > now {
+			continue
+		}
+		for id, amount := range goods {
+			s.ready[id] += amount
+		}
+		delete(s.incoming, at)
+	}
+}
+
+// Next moment after now and before the end that something comes off a line
+func (s *sessionStock) nextArrival(now, end time.Duration) (time.Duration, bool) {
+	var next time.Duration
+	found := false
+	for at := range s.incoming {
+		if at > now && at < end && (!found || at < next) {
+			next, found = at, true
+		}
+	}
+	return next, found
+}
+
+// Candidates of a source plus the goods of it other lines are waiting for,
+// ingredients do not have to be worth selling on their own
+func (p *SessionPlanner) withDemanded(candidates models.HayDayGoodList, demand map[uuid.UUID]int) models.HayDayGoodList {
+	if len(candidates) == 0 {
+		return nil
+	}
+	source := candidates[0].Source
+
+	result := candidates
+	for id := range demand {
+		good := p.goodsMap[id]
+		if good.Source == source && good.ProductionTime > 0 && !containsGood(candidates, id) {
+			result = append(result[:len(result):len(result)], good)
+		}
+	}
+	return result
+}
+
+func (p *SessionPlanner) makeable(candidates models.HayDayGoodList, ready map[uuid.UUID]int) models.HayDayGoodList {
+	var result models.HayDayGoodList
+	for _, good := range candidates {
+		if p.inStock(good, ready) {
+			result = append(result, good)
+		}
+	}
+	return result
+}
+
+func (p *SessionPlanner) inStock(good models.HayDayGood, ready map[uuid.UUID]int) bool {
+	for _, ingredient := range good.Ingredients {
+		if ready[ingredient.ProductID] < ingredient.Amount {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *SessionPlanner) profitableGoods(goods models.HayDayGoodList) models.HayDayGoodList {
+	var result models.HayDayGoodList
+	for _, good := range goods {
+		if good.ProductionTime > 0 && p.optimizer.netValue(good) > 0 {
+			result = append(result, good)
+		}
+	}
+	return result
+}
+
+func (p *SessionPlanner) bestRateWithin(candidates models.HayDayGoodList, remaining time.Duration) (models.HayDayGood, bool) {
+	var best models.HayDayGood
+	bestRate := 0.0
+	for _, good := range candidates {
+		if good.ProductionTime > remaining {
+			continue
+		}
+		rate := float64(p.optimizer.netValue(good)*models.BatchYield(good.Source)) / good.ProductionTime.Hours()
+		if rate > bestRate {
+			best = good
+			bestRate = rate
+		}
+	}
+	return best, bestRate > 0
+}
+
+// Most valuable set of at most slots jobs that run back to back within the
+// remaining time, solved as a small knapsack over whole minutes
+func (p *SessionPlanner) fillGap(candidates models.HayDayGoodList, remaining time.Duration, slots int) models.HayDayGoodList {
+	minutes := int(remaining / time.Minute)
+	if minutes <= 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	// value[k][m] is the best value of k jobs fitting into m minutes
+	value := make([][]int, slots+1)
+	choice := make([][]int, slots+1)
+	for k := range value {
+		value[k] = make([]int, minutes+1)
+		choice[k] = make([]int, minutes+1)
+		for m := range choice[k] {
+			choice[k][m] = -1
+		}
+	}
+
+	for k := 1; k <= slots; k++ {
+		for m := 0; m <= minutes; m++ {
+			value[k][m] = value[k-1][m]
+			for i, good := range candidates {
+				duration := int((good.ProductionTime + time.Minute - 1) / time.Minute)
+				if duration > m {
+					continue
+				}
+				if candidate := value[k-1][m-duration] + p.optimizer.netValue(good)*models.BatchYield(good.Source); candidate > value[k][m] {
+					value[k][m] = candidate
+					choice[k][m] = i
+				}
+			}
+		}
+	}
+
+	var jobs models.HayDayGoodList
+	for k, m := slots, minutes; k > 0; k-- {
+		i := choice[k][m]
+		if i == -1 {
+			continue
+		}
+		jobs = append(jobs, candidates[i])
+		m -= int((candidates[i].ProductionTime + time.Minute - 1) / time.Minute)
+	}
+
+	// Short jobs first so the longest one is the one left running
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].ProductionTime < jobs[j].ProductionTime
+	})
+	return jobs
+}
+
+func containsGood(goods models.HayDayGoodList, id uuid.UUID) bool {
+	for _, good := range goods {
+		if good.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestSessionPlannerUsesOwnedLinesAndStock(t *testing.T) {
+	tests := []struct {
+		name      string
+		capacity  map[string]int
+		inventory map[string]int
+		bread     int
+	}{
+		{name: "no bakery", capacity: map[string]int{models.SourceField: 2, "Bakery": 0}, inventory: map[string]int{"Wheat": 30}},
+		{name: "bakery without wheat", capacity: map[string]int{"Bakery": 1}},
+		{name: "wheat for two loaves", capacity: map[string]int{"Bakery": 1}, inventory: map[string]int{"Wheat": 6}, bread: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := testRepository()
+			state := models.PlayerState{Level: 5, Capacity: test.capacity, Inventory: test.inventory}
+
+			plan := NewSessionPlanner(repo.GetAllGoods()).Plan(availableGoods(repo, state), state, 30*time.Minute, 8*time.Hour)
+
+			bread := 0
+			type lineKey struct {
+				source string
+				index  int
+			}
+			lastReady := make(map[lineKey]time.Duration)
+			for _, task := range plan.Tasks {
+				if test.capacity[task.Source] == 0 {
+					t.Errorf("task %+v on a source the farm does not have", task)
+				}
+				if task.Good == "Bread" {
+					bread++
+				}
+				line := lineKey{task.Source, task.Line}
+				if task.StartAt < lastReady[line] {
+					t.Errorf("task %+v starts before the line is free at %v", task, lastReady[line])
+				}
+				lastReady[line] = task.ReadyAt
+			}
+			if bread != test.bread {
+				t.Errorf("bread = %d, want %d", bread, test.bread)
+			}
+		})
+	}
+}
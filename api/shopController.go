@@ -0,0 +1,39 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type ShopController struct {
+	repo *GoodsRepository
+}
+
+func NewShopController(repo *GoodsRepository) *ShopController {
+	return &ShopController{
+		repo: repo,
+	}
+}
+
+func (a *ShopController) Init(router *http.ServeMux) {
+	router.HandleFunc("POST /shop/listings", a.planListings)
+}
+
+func (a *ShopController) planListings(w http.ResponseWriter, r *http.Request) {
+	var request models.ShopRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := NewShopPlanner(a.repo).Plan(request)
+	if err != nil {
+		http.Error(w, err.Error(), inputErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(plan)
+}
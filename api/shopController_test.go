@@ -0,0 +1,15 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestShopControllerStatus(t *testing.T) {
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "listings", method: http.MethodPost, path: "/shop/listings", body: `{"Inventory":{"Bread":12},"Slots":2}`, status: http.StatusOK},
+		{name: "no slots", method: http.MethodPost, path: "/shop/listings", body: `{"Inventory":{"Bread":12}}`, status: http.StatusBadRequest},
+		{name: "unknown good", method: http.MethodPost, path: "/shop/listings", body: `{"Inventory":{"Gold":1},"Slots":2}`, status: http.StatusBadRequest},
+		{name: "malformed", method: http.MethodPost, path: "/shop/listings", body: `[`, status: http.StatusBadRequest},
+	})
+}
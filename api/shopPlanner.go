@@ -0,0 +1,115 @@
+package api
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const (
+	// Most units of one good a roadside shop slot can hold
+	maxStackSize       = 10
+	defaultRelistHours = 24
+	hoursPerDay        = 24.0
+)
+
+// Picks what to put into the roadside shop. Every stack is listed at the
+// highest allowed price. A slot only earns its full value each cycle if the
+// stack can be made again within that cycle, slower goods earn proportionally less.
+type ShopPlanner struct {
+	repo *GoodsRepository
+}
+
+func NewShopPlanner(repo *GoodsRepository) *ShopPlanner {
+	return &ShopPlanner{
+		repo: repo,
+	}
+}
+
+func (p *ShopPlanner) Plan(request models.ShopRequest) (models.ShopPlan, error) {
+	if request.Slots < 1 {
+		return models.ShopPlan{}, base.ErrInvalidQuantity
+	}
+
+	relistHours := request.RelistHours
+	if relistHours <= 0 {
+		relistHours = defaultRelistHours
+	}
+	cycle := time.Duration(relistHours) * time.Hour
+
+	remaining := make(map[string]int)
+	var goods models.HayDayGoodList
+	for name, amount := range request.Inventory {
+		good, err := p.repo.GetGoodByName(name)
+		if err != nil {
+			return models.ShopPlan{}, err
+		}
+		if amount > 0 && good.MaxPrice > 0 {
+			remaining[good.Name] = amount
+			goods = append(goods, *good)
+		}
+	}
+	sort.Slice(goods, func(i, j int) bool {
+		return goods[i].Name < goods[j].Name
+	})
+
+	plan := models.ShopPlan{
+		Slots:    request.Slots,
+		Listings: []models.ShopListing{},
+	}
+
+	// Restock time already committed per source by earlier listings
+	committed := make(map[string]time.Duration)
+
+	for range request.Slots {
+		var best models.ShopListing
+		var bestGood models.HayDayGood
+		found := false
+
+		for _, good := range goods {
+			quantity := min(remaining[good.Name], maxStackSize)
+			if quantity == 0 {
+				continue
+			}
+
+			listing := p.listing(good, quantity, committed[good.Source], cycle)
+			if !found || listing.CoinsPerDay > best.CoinsPerDay {
+				best, bestGood, found = listing, good, true
+			}
+		}
+		if !found {
+			break
+		}
+
+		remaining[bestGood.Name] -= best.Quantity
+		committed[bestGood.Source] += best.RestockTime
+		plan.Listings = append(plan.Listings, best)
+		plan.CoinsPerDay += best.CoinsPerDay
+	}
+
+	plan.UnusedSlots = request.Slots - len(plan.Listings)
+	return plan, nil
+}
+
+func (p *ShopPlanner) listing(good models.HayDayGood, quantity int, committed, cycle time.Duration) models.ShopListing {
+	restock := good.ProductionTime * time.Duration(models.Batches(good.Source, quantity))
+	price := good.MaxPrice * quantity
+
+	// Share of the cycles in which the source can keep this stack filled,
+	// counting the time it already spends restocking other listings
+	sustainable := 1.0
+	if total := committed + restock; total > cycle {
+		sustainable = math.Max(0, float64(cycle-committed)) / float64(restock)
+	}
+
+	return models.ShopListing{
+		Good:        good.Name,
+		Quantity:    quantity,
+		Price:       price,
+		RestockTime: restock,
+		CoinsPerDay: float64(price) * sustainable * hoursPerDay / cycle.Hours(),
+	}
+}
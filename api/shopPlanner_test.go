@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestShopPlannerRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		request models.ShopRequest
+		want    error
+	}{
+		{name: "no slots", request: models.ShopRequest{Inventory: map[string]int{"Bread": 5}}, want: base.ErrInvalidQuantity},
+		{name: "negative slots", request: models.ShopRequest{Inventory: map[string]int{"Bread": 5}, Slots: -1}, want: base.ErrInvalidQuantity},
+		{name: "unknown good", request: models.ShopRequest{Inventory: map[string]int{"Gold": 5}, Slots: 2}, want: base.ErrNoGoodByNameFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewShopPlanner(testRepository()).Plan(test.request)
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestShopPlannerFillsSlots(t *testing.T) {
+	// Bread goes out in full stacks first, the wheat only earns its price
+	plan, err := NewShopPlanner(testRepository()).Plan(models.ShopRequest{
+		Inventory: map[string]int{"Bread": 25, "Wheat": 3},
+		Slots:     5,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []models.ShopListing{
+		{Good: "Bread", Quantity: 10, Price: 210},
+		{Good: "Bread", Quantity: 10, Price: 210},
+		{Good: "Bread", Quantity: 5, Price: 105},
+		{Good: "Wheat", Quantity: 3, Price: 9},
+	}
+	if len(plan.Listings) != len(want) {
+		t.Fatalf("listings = %+v, want %d", plan.Listings, len(want))
+	}
+	for i, listing := range plan.Listings {
+		if listing.Good != want[i].Good || listing.Quantity != want[i].Quantity || listing.Price != want[i].Price {
+			t.Errorf("listing %d = %+v, want %+v", i, listing, want[i])
+		}
+	}
+	if plan.UnusedSlots != 1 || plan.CoinsPerDay != 534 {
+		t.Errorf("unused slots = %d, coins per day = %v, want 1 and 534", plan.UnusedSlots, plan.CoinsPerDay)
+	}
+}
+
+func TestShopPlannerSlowRestock(t *testing.T) {
+	// Ten corn breads keep the bakery busy for five hours, relisted every
+	// hour the slot is only full one cycle in five
+	plan, err := NewShopPlanner(testRepository()).Plan(models.ShopRequest{
+		Inventory:   map[string]int{"Corn bread": 10},
+		Slots:       1,
+		RelistHours: 1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(plan.Listings) != 1 {
+		t.Fatalf("listings = %+v, want one", plan.Listings)
+	}
+	if want := 720.0 * 0.2 * 24; plan.Listings[0].CoinsPerDay != want {
+		t.Errorf("coins per day = %v, want %v", plan.Listings[0].CoinsPerDay, want)
+	}
+}
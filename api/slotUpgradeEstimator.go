@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const (
+	sortByCoins = "coins"
+	sortByXP    = "xp"
+)
+
+// Values one extra queue slot per owned machine. The chosen strategy plans the
+// profile, and every machine earns what its planned goods make per busy hour.
+// While playing a machine is kept busy, during the offline gap it only works
+// through the jobs queued before leaving, so each slot covers one more job of
+// the gap. The difference is scaled to a day of sessions and offline gaps.
+type SlotUpgradeEstimator struct {
+	repo       *GoodsRepository
+	strategies *StrategyRegistry
+}
+
+// What the planned goods of one machine are worth while it is busy
+type machineRate struct {
+	coinsPerHour float64
+	xpPerHour    float64
+	jobLength    time.Duration
+}
+
+func NewSlotUpgradeEstimator(repo *GoodsRepository, strategies *StrategyRegistry) *SlotUpgradeEstimator {
+	return &SlotUpgradeEstimator{
+		repo:       repo,
+		strategies: strategies,
+	}
+}
+
+func (e *SlotUpgradeEstimator) Estimate(ctx context.Context, strategyName string, state models.PlayerState, session, offline time.Duration, sortBy string) (models.SlotUpgradeReport, error) {
+	if sortBy != sortByXP {
+		sortBy = sortByCoins
+	}
+
+	plan, err := runStrategy(ctx, e.strategies, strategyName, state)
+	if err != nil {
+		return models.SlotUpgradeReport{}, err
+	}
+
+	report := models.SlotUpgradeReport{
+		Strategy:   plan.Strategy,
+		Session:    session,
+		OfflineGap: offline,
+		SortedBy:   sortBy,
+		Upgrades:   []models.SlotUpgrade{},
+	}
+
+	cyclesPerDay := 0.0
+	if cycle := session + offline; cycle > 0 {
+		cyclesPerDay = hoursPerDay / cycle.Hours()
+	}
+
+	rates := machineRates(plan)
+	for source := range groupGoodsBySource(availableGoods(e.repo, state)) {
+		lines := sourceCapacity(state, source)
+		if !models.IsMachineSource(source) || lines == 0 {
+			continue
+		}
+
+		// Machines the plan leaves idle gain nothing from another slot
+		slots := queueSlots(state, source)
+		rate := rates[source]
+		extraHours := 0.0
+		if rate.jobLength > 0 {
+			before := min(offline, time.Duration(slots)*rate.jobLength)
+			after := min(offline, time.Duration(slots+1)*rate.jobLength)
+			extraHours = (after - before).Hours() * float64(lines) * cyclesPerDay
+		}
+
+		report.Upgrades = append(report.Upgrades, models.SlotUpgrade{
+			Source:           source,
+			Slots:            slots,
+			ExtraCoinsPerDay: rate.coinsPerHour * extraHours,
+			ExtraXPPerDay:    rate.xpPerHour * extraHours,
+		})
+	}
+
+	sort.Slice(report.Upgrades, func(i, j int) bool {
+		a, b := report.Upgrades[i], report.Upgrades[j]
+		if sortBy == sortByXP && a.ExtraXPPerDay != b.ExtraXPPerDay {
+			return a.ExtraXPPerDay > b.ExtraXPPerDay
+		}
+		if a.ExtraCoinsPerDay != b.ExtraCoinsPerDay {
+			return a.ExtraCoinsPerDay > b.ExtraCoinsPerDay
+		}
+		return a.Source < b.Source
+	})
+
+	return report, nil
+}
+
+// Earnings per busy hour of a line and the average job length of every
+// machine, taken from the planned quantities
+func machineRates(plan models.Plan) map[string]machineRate {
+	type totals struct {
+		coins, xp float64
+		busy      time.Duration
+		jobs      int
+	}
+	bySource := make(map[string]*totals)
+	for _, good := range plan.Goods {
+		if !models.IsMachineSource(good.Source) || good.Quantity <= 0 || good.ProductionTime <= 0 {
+			continue
+		}
+		if bySource[good.Source] == nil {
+			bySource[good.Source] = &totals{}
+		}
+		batches := models.Batches(good.Source, good.Quantity)
+		total := bySource[good.Source]
+		total.coins += float64(good.NetValue * good.Quantity)
+		total.xp += float64(good.GainedXP * batches)
+		total.busy += time.Duration(batches) * good.ProductionTime
+		total.jobs += batches
+	}
+
+	rates := make(map[string]machineRate)
+	for source, total := range bySource {
+		rates[source] = machineRate{
+			coinsPerHour: total.coins / total.busy.Hours(),
+			xpPerHour:    total.xp / total.busy.Hours(),
+			jobLength:    total.busy / time.Duration(total.jobs),
+		}
+	}
+	return rates
+}
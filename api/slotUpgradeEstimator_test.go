@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func testSlotUpgradeEstimator() *SlotUpgradeEstimator {
+	repo := testRepository()
+	return NewSlotUpgradeEstimator(repo, NewDefaultStrategyRegistry(repo))
+}
+
+func TestSlotUpgradeEstimatorOwnedMachinesOnly(t *testing.T) {
+	// Fields and chickens hold a single job, the bakery is not built yet
+	state := models.PlayerState{
+		Level:    5,
+		Capacity: map[string]int{models.SourceField: 4, models.SourceFeedMill: 1, "Chicken": 3, "Bakery": 0},
+	}
+
+	tests := []struct {
+		name    string
+		sortBy  string
+		sorted  string
+		offline time.Duration
+		gains   bool
+	}{
+		{name: "coins", sortBy: "coins", sorted: "coins", offline: 8 * time.Hour, gains: true},
+		{name: "xp", sortBy: "xp", sorted: "xp", offline: 8 * time.Hour, gains: true},
+		{name: "unknown order", sortBy: "fun", sorted: "coins", offline: 8 * time.Hour, gains: true},
+		{name: "never offline", sortBy: "coins", sorted: "coins", offline: 0, gains: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := testSlotUpgradeEstimator().Estimate(context.Background(), "lp", state, 30*time.Minute, test.offline, test.sortBy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if report.SortedBy != test.sorted {
+				t.Errorf("sorted by = %q, want %q", report.SortedBy, test.sorted)
+			}
+			if len(report.Upgrades) != 1 || report.Upgrades[0].Source != models.SourceFeedMill {
+				t.Fatalf("upgrades = %+v, want only the Feed Mill", report.Upgrades)
+			}
+			if gains := report.Upgrades[0].ExtraCoinsPerDay > 0; gains != test.gains {
+				t.Errorf("extra coins per day = %v, want gains %v", report.Upgrades[0].ExtraCoinsPerDay, test.gains)
+			}
+		})
+	}
+}
+
+func TestSlotUpgradeEstimatorUnknownStrategy(t *testing.T) {
+	_, err := testSlotUpgradeEstimator().Estimate(context.Background(), "nope", models.PlayerState{Level: 5}, time.Hour, time.Hour, "")
+	if !errors.Is(err, base.ErrUnknownStrategy) {
+		t.Errorf("err = %v, want %v", err, base.ErrUnknownStrategy)
+	}
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"sort"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type Strategy interface {
+	Plan(ctx context.Context, state models.PlayerState) (models.Plan, error)
+}
+
+// Strategies that can plan the same way over other goods data, the
+// sensitivity analysis runs them on changed prices and production times
+type GoodsBoundStrategy interface {
+	Strategy
+	WithGoods(repo *GoodsRepository) Strategy
+}
+
+type StrategyRegistry struct {
+	strategies map[string]Strategy
+}
+
+func NewStrategyRegistry() *StrategyRegistry {
+	return &StrategyRegistry{
+		strategies: make(map[string]Strategy),
+	}
+}
+
+// Every strategy the server offers, planning over the goods in repo
+func NewDefaultStrategyRegistry(repo *GoodsRepository) *StrategyRegistry {
+	registry := NewStrategyRegistry()
+	registry.Register("legacy-greedy", NewGreedyStrategy(repo, MetricMaxPrice))
+	registry.Register("greedy", NewGreedyStrategy(repo, MetricNetProfit))
+	registry.Register("coins_per_hour", NewGreedyStrategy(repo, MetricCoinsPerHour))
+	registry.Register("coins_per_lead_hour", NewGreedyStrategy(repo, MetricCoinsPerLeadHour))
+	registry.Register("xp", NewXPStrategy(repo, MetricXPPerHour))
+	registry.Register("xp_per_ingredient", NewXPStrategy(repo, MetricXPPerIngredient))
+	registry.Register("lp", NewLPStrategy(repo))
+	return registry
+}
+
+func (r *StrategyRegistry) Register(name string, strategy Strategy) {
+	r.strategies[name] = strategy
+}
+
+func (r *StrategyRegistry) Get(name string) (Strategy, error) {
+	strategy, exists := r.strategies[name]
+	if !exists {
+		return nil, base.ErrUnknownStrategy
+	}
+	return strategy, nil
+}
+
+func (r *StrategyRegistry) Names() []string {
+	names := make([]string, 0, len(r.strategies))
+	for name := range r.strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
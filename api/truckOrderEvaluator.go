@@ -0,0 +1,98 @@
+package api
+
+import (
+	"math"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Orders paying less than this share of their cost are not worth waiting for
+const refreshRewardRatio = 0.5
+
+type TruckOrderEvaluator struct {
+	repo      *GoodsRepository
+	optimizer *Optimizer
+	expander  *BOMExpander
+}
+
+func NewTruckOrderEvaluator(repo *GoodsRepository) *TruckOrderEvaluator {
+	return &TruckOrderEvaluator{
+		repo:      repo,
+		optimizer: NewOptimizer(repo.GetAllGoods(), MetricNetProfit),
+		expander:  NewBOMExpander(repo.GetAllGoods()),
+	}
+}
+
+func (e *TruckOrderEvaluator) Evaluate(order models.TruckOrder) (models.TruckOrderEvaluation, error) {
+	evaluation := models.TruckOrderEvaluation{
+		ReplacementTime: make(map[string]time.Duration),
+		LockedGoods:     []string{},
+	}
+
+	for _, item := range order.Goods {
+		if item.Amount < 1 {
+			return evaluation, base.ErrInvalidQuantity
+		}
+
+		good, err := e.repo.GetGoodByName(item.Name)
+		if err != nil {
+			return evaluation, err
+		}
+		if order.Level > 0 && good.RequiredLevel > order.Level {
+			evaluation.LockedGoods = append(evaluation.LockedGoods, good.Name)
+		}
+
+		evaluation.SaleValue += good.MaxPrice * item.Amount
+
+		bom := e.expander.Expand(*good, item.Amount)
+		for source, duration := range bom.MachineTime {
+			evaluation.ReplacementTime[source] += duration
+		}
+	}
+
+	rates := e.bestRatePerSource(order.Level)
+	replacementCost := 0.0
+	for source, duration := range evaluation.ReplacementTime {
+		replacementCost += duration.Hours() * rates[source]
+	}
+	evaluation.ReplacementCost = int(math.Round(replacementCost))
+
+	evaluation.OpportunityCost = evaluation.SaleValue + evaluation.ReplacementCost
+	evaluation.RewardValue = order.Coins + int(math.Round(float64(order.XP)*order.CoinsPerXP))
+	evaluation.Balance = evaluation.RewardValue - evaluation.OpportunityCost
+
+	switch {
+	case len(evaluation.LockedGoods) > 0:
+		evaluation.Recommendation = models.RecommendRefresh
+	case evaluation.Balance >= 0:
+		evaluation.Recommendation = models.RecommendFill
+	case float64(evaluation.RewardValue) < refreshRewardRatio*float64(evaluation.OpportunityCost):
+		evaluation.Recommendation = models.RecommendRefresh
+	default:
+		evaluation.Recommendation = models.RecommendSkip
+	}
+
+	return evaluation, nil
+}
+
+// Net coins per hour of the best good each source can make at the level
+func (e *TruckOrderEvaluator) bestRatePerSource(level int) map[string]float64 {
+	goods := e.repo.GetAllGoods()
+	if level > 0 {
+		goods = e.repo.GetGoodsByLevel(level)
+	}
+
+	rates := make(map[string]float64)
+	for source, sourceGoods := range groupGoodsBySource(goods) {
+		for _, good := range sourceGoods {
+			if good.ProductionTime <= 0 {
+				continue
+			}
+			rate := float64(e.optimizer.netValue(good)) / good.ProductionTime.Hours()
+			rates[source] = max(rates[source], rate)
+		}
+	}
+	return rates
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestTruckOrderEvaluatorRejectsInvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		goods []models.OrderItem
+		want  error
+	}{
+		{name: "empty item", goods: []models.OrderItem{{Name: "Bread", Amount: 0}}, want: base.ErrInvalidQuantity},
+		{name: "negative item", goods: []models.OrderItem{{Name: "Bread", Amount: -2}}, want: base.ErrInvalidQuantity},
+		{name: "unknown good", goods: []models.OrderItem{{Name: "Gold", Amount: 1}}, want: base.ErrNoGoodByNameFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewTruckOrderEvaluator(testRepository()).Evaluate(models.TruckOrder{Level: 5, Goods: test.goods})
+			if !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestTruckOrderEvaluatorRecommendation(t *testing.T) {
+	evaluator := NewTruckOrderEvaluator(testRepository())
+	bread := []models.OrderItem{{Name: "Bread", Amount: 2}}
+
+	// What the bread and the time to make it again are worth
+	priced, err := evaluator.Evaluate(models.TruckOrder{Level: 5, Goods: bread})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cost := priced.OpportunityCost
+	if priced.SaleValue != 42 || cost <= priced.SaleValue {
+		t.Fatalf("sale value = %d, opportunity cost = %d, want 42 and more", priced.SaleValue, cost)
+	}
+
+	tests := []struct {
+		name  string
+		order models.TruckOrder
+		want  models.OrderRecommendation
+	}{
+		{name: "pays more than it costs", order: models.TruckOrder{Level: 5, Goods: bread, Coins: cost}, want: models.RecommendFill},
+		{name: "experience makes up the rest", order: models.TruckOrder{Level: 5, Goods: bread, Coins: cost - 10, XP: 5, CoinsPerXP: 2}, want: models.RecommendFill},
+		{name: "slightly short", order: models.TruckOrder{Level: 5, Goods: bread, Coins: cost - 1}, want: models.RecommendSkip},
+		{name: "far too little", order: models.TruckOrder{Level: 5, Goods: bread, Coins: cost/2 - 1}, want: models.RecommendRefresh},
+		{name: "locked good", order: models.TruckOrder{Level: 1, Goods: bread, Coins: 10 * cost}, want: models.RecommendRefresh},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evaluation, err := evaluator.Evaluate(test.order)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if evaluation.Recommendation != test.want {
+				t.Errorf("recommendation = %s, want %s (balance %d)", evaluation.Recommendation, test.want, evaluation.Balance)
+			}
+		})
+	}
+}
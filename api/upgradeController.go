@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+type UpgradeController struct {
+	repo       *GoodsRepository
+	profiles   *ProfileRepository
+	strategies *StrategyRegistry
+}
+
+func NewUpgradeController(repo *GoodsRepository, profiles *ProfileRepository, strategies *StrategyRegistry) *UpgradeController {
+	return &UpgradeController{
+		repo:       repo,
+		profiles:   profiles,
+		strategies: strategies,
+	}
+}
+
+func (a *UpgradeController) Init(router *http.ServeMux) {
+	router.HandleFunc("POST /upgrades/purchase/evaluate", a.evaluatePurchase)
+	router.HandleFunc("GET /upgrades/slots/{id}", a.estimateSlotUpgrades)
+}
+
+func (a *UpgradeController) evaluatePurchase(w http.ResponseWriter, r *http.Request) {
+	var request models.PurchaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, base.ErrFailedJSONParse.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id, err := parseProfileID(request.ProfileID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	profile, err := a.profiles.GetProfileByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	evaluator := NewPurchaseEvaluator(a.repo, a.strategies)
+
+	evaluation, err := evaluator.Evaluate(r.Context(), playerStateFromProfile(*profile), request)
+	if err != nil {
+		http.Error(w, err.Error(), planningErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(evaluation)
+}
+
+func (a *UpgradeController) estimateSlotUpgrades(w http.ResponseWriter, r *http.Request) {
+	id, err := parseProfileID(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	profile, err := a.profiles.GetProfileByID(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	session, offline, err := parseSessionWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	strategyName, err := parseStrategyName(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	estimator := NewSlotUpgradeEstimator(a.repo, a.strategies)
+
+	report, err := estimator.Estimate(r.Context(), strategyName, playerStateFromProfile(*profile), session, offline, r.URL.Query().Get("sort"))
+	if err != nil {
+		http.Error(w, err.Error(), strategyErrorStatus(err))
+		return
+	}
+
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUpgradeControllerStatus(t *testing.T) {
+	unknown := "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+
+	runHandlerTests(t, testRouter(t), []handlerTest{
+		{name: "purchase without profile", method: http.MethodPost, path: "/upgrades/purchase/evaluate", body: `{"Source":"Bakery","Cost":500}`, status: http.StatusNotFound},
+		{name: "purchase unknown profile", method: http.MethodPost, path: "/upgrades/purchase/evaluate", body: `{"ProfileID":"` + unknown + `","Source":"Bakery","Cost":500}`, status: http.StatusNotFound},
+		{name: "purchase malformed", method: http.MethodPost, path: "/upgrades/purchase/evaluate", body: `{"Source":`, status: http.StatusBadRequest},
+		{name: "slots unknown profile", method: http.MethodGet, path: "/upgrades/slots/" + unknown, status: http.StatusNotFound},
+	})
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Picks the goods that level the player up fastest, ranked by experience per
+// hour or per ingredient of their whole chain
+type XPStrategy struct {
+	repo   *GoodsRepository
+	metric RankingMetric
+}
+
+func NewXPStrategy(repo *GoodsRepository, metric RankingMetric) *XPStrategy {
+	return &XPStrategy{
+		repo:   repo,
+		metric: metric,
+	}
+}
+
+func (s *XPStrategy) WithGoods(repo *GoodsRepository) Strategy {
+	return NewXPStrategy(repo, s.metric)
+}
+
+func (s *XPStrategy) Plan(ctx context.Context, state models.PlayerState) (models.Plan, error) {
+	xpToTarget := state.XPToTarget
+	if xpToTarget == 0 && state.TargetLevel > state.Level {
+		var err error
+		xpToTarget, err = xpBetweenLevels(state.XPToNextLevel, state.Level, state.TargetLevel)
+		if err != nil {
+			return models.Plan{}, err
+		}
+	}
+
+	optimizer := NewOptimizer(s.repo.GetAllGoods(), s.metric).withLifecycles(state.Lifecycles)
+
+	goods := optimizer.GetOptimizedPlan(availableGoods(s.repo, state))
+
+	plan := models.Plan{
+		Goods:     goods,
+		Decisions: optimizer.Decisions(),
+	}
+
+	// Ingredients are made on the same sources, so the plan levels up slower
+	// than its goods on their own would
+	xpPerHour, err := evaluatePlan(s.repo, state, &plan)
+	if err != nil {
+		return models.Plan{}, err
+	}
+	plan.XPPerHour = xpPerHour
+
+	if xpToTarget > 0 && plan.XPPerHour > 0 {
+		plan.HoursToTarget = float64(xpToTarget) / plan.XPPerHour
+	}
+
+	return plan, nil
+}
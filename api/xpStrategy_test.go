@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func TestXPStrategyHoursToTarget(t *testing.T) {
+	tests := []struct {
+		name  string
+		state models.PlayerState
+		xp    int
+		want  error
+	}{
+		{name: "no target", state: models.PlayerState{Level: 5}},
+		{name: "experience given", state: models.PlayerState{Level: 5, XPToTarget: 500}, xp: 500},
+		{name: "level table", state: models.PlayerState{Level: 5, TargetLevel: 7, XPToNextLevel: map[int]int{5: 100, 6: 200}}, xp: 300},
+		{name: "level missing from table", state: models.PlayerState{Level: 5, TargetLevel: 7, XPToNextLevel: map[int]int{5: 100}}, want: base.ErrMissingLevelXP},
+		{name: "no table", state: models.PlayerState{Level: 5, TargetLevel: 7}, want: base.ErrMissingLevelXP},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			plan, err := NewXPStrategy(testRepository(), MetricXPPerHour).Plan(context.Background(), test.state)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("err = %v, want %v", err, test.want)
+			}
+			if err != nil {
+				return
+			}
+
+			if plan.XPPerHour <= 0 {
+				t.Fatalf("xp per hour = %v, want some", plan.XPPerHour)
+			}
+			want := 0.0
+			if test.xp > 0 {
+				want = float64(test.xp) / plan.XPPerHour
+			}
+			if math.Abs(plan.HoursToTarget-want) > 1e-9 {
+				t.Errorf("hours to target = %v, want %v", plan.HoursToTarget, want)
+			}
+		})
+	}
+}
@@ -12,4 +12,26 @@ var (
 	ErrFailedToReadFile        = errors.New("Failed to read file")
 	ErrFailedJSONParse         = errors.New("Failed to parse JSON")
 	ErrFailedToWriteFile       = errors.New("Failed to write file")
+	ErrUnknownStrategy         = errors.New("Unknown strategy")
+	ErrUnknownRankingMetric    = errors.New("Unknown ranking metric")
+	ErrStrategyMetricConflict  = errors.New("Strategy and metric select different plans")
+	ErrProblemInfeasible       = errors.New("Optimization problem has no feasible solution")
+	ErrProblemUnbounded        = errors.New("Optimization problem is unbounded")
+	ErrSolverIterationLimit    = errors.New("Solver exceeded its iteration limit")
+	ErrNoIntegerSolution       = errors.New("No integer solution found within the search limits")
+	ErrInvalidQuantity         = errors.New("Quantity must be a positive number")
+	ErrInvalidInventory        = errors.New("Inventory counts must not be negative")
+	ErrInvalidCapacity         = errors.New("Capacities must not be negative")
+	ErrNoProfileByIDFound      = errors.New("Error no profile with that id found")
+	ErrInvalidProfile          = errors.New("Profile is missing required fields or has negative counts")
+	ErrUnknownProfileSource    = errors.New("Profile names a source that does not exist or is listed under the wrong kind")
+	ErrUnknownDerbyTaskType    = errors.New("Unknown derby task type")
+	ErrDeadlinePassed          = errors.New("Deadline is not in the future")
+	ErrNoSourceByNameFound     = errors.New("Error no source with that name found at this level")
+	ErrInvalidLevelRange       = errors.New("Level range is empty or too long")
+	ErrMissingLevelXP          = errors.New("Experience to the next level is missing for a level in the range")
+	ErrStrategyNotPerturbable  = errors.New("Strategy cannot be run on changed goods data")
+	ErrInvalidPercentage       = errors.New("Percentage must be above 0 and below 100")
+	ErrInvalidWeights          = errors.New("Objective weights must not be negative and not all zero")
+	ErrSessionTooLong          = errors.New("Session and offline gap together must not exceed 48 hours")
 )
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/api"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const chartWidth = 72
+
+func printSessionPlan(goodsRepository *api.GoodsRepository, level int, session, offline time.Duration) {
+	planner := api.NewSessionPlanner(goodsRepository.GetAllGoods())
+	plan := planner.Plan(goodsRepository.GetGoodsByLevel(level), models.PlayerState{Level: level}, session, offline)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "START\tREADY\tSOURCE\tGOOD\tCOLLECT")
+	for _, task := range plan.Tasks {
+		collect := "now"
+		if task.CollectAfterGap {
+			collect = "next session"
+		}
+		fmt.Fprintf(writer, "+%s\t+%s\t%s #%d\t%s\t%s\n", task.StartAt, task.ReadyAt, task.Source, task.Line+1, task.Good, collect)
+	}
+	writer.Flush()
+
+	fmt.Printf("\nExpected: %d coins, %d XP\n", plan.Coins, plan.XP)
+}
+
+// Gantt chart with one row per slot, every job is drawn with the first letter
+// of its good and idle time as dots
+func printSchedule(goodsRepository *api.GoodsRepository, strategies *api.StrategyRegistry, strategyName string, state models.PlayerState) error {
+	strategy, err := strategies.Get(strategyName)
+	if err != nil {
+		return err
+	}
+
+	plan, err := strategy.Plan(context.Background(), state)
+	if err != nil {
+		return err
+	}
+
+	scheduler := api.NewProductionScheduler(goodsRepository.GetAllGoods())
+	schedule := scheduler.Schedule(plan.Goods, state, time.Now())
+
+	step := schedule.Horizon / chartWidth
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "SLOT\t0%s%s\tGOODS\n", strings.Repeat(" ", chartWidth-len(schedule.Horizon.String())-1), schedule.Horizon)
+	for _, timeline := range schedule.Timelines {
+		row := []byte(strings.Repeat(".", chartWidth))
+		var goods []string
+		seen := make(map[string]bool)
+		for _, job := range timeline.Jobs {
+			from := int(job.Start.Sub(schedule.Start) / step)
+			to := max(int(job.Finish.Sub(schedule.Start)/step), from+1)
+			for column := from; column < to && column < chartWidth; column++ {
+				row[column] = strings.ToUpper(job.Good)[0]
+			}
+			if !seen[job.Good] {
+				seen[job.Good] = true
+				goods = append(goods, job.Good)
+			}
+		}
+		fmt.Fprintf(writer, "%s #%d\t%s\t%s\n", timeline.Source, timeline.Slot+1, row, strings.Join(goods, ", "))
+	}
+	writer.Flush()
+
+	unscheduled := make([]string, 0, len(schedule.Unscheduled))
+	for good := range schedule.Unscheduled {
+		unscheduled = append(unscheduled, good)
+	}
+	sort.Strings(unscheduled)
+	for _, good := range unscheduled {
+		fmt.Printf("Does not fit: %d x %s\n", schedule.Unscheduled[good], good)
+	}
+
+	return nil
+}
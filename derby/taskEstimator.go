@@ -0,0 +1,171 @@
+package derby
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Expands a good into everything needed to make a quantity of it
+type ChainExpander interface {
+	Expand(good models.HayDayGood, quantity int) models.BillOfMaterials
+}
+
+// Estimates how long derby tasks take on a farm and whether they are worth it
+type TaskEstimator struct {
+	availableGoods models.HayDayGoodList
+	expander       ChainExpander
+	state          models.PlayerState
+}
+
+func NewTaskEstimator(availableGoods models.HayDayGoodList, expander ChainExpander, state models.PlayerState) *TaskEstimator {
+	return &TaskEstimator{
+		availableGoods: availableGoods,
+		expander:       expander,
+		state:          state,
+	}
+}
+
+// Evaluate every task, tasks done straight from storage first and the rest by
+// best points per hour
+func (e *TaskEstimator) EvaluateAll(tasks []models.DerbyTask, minPointsPerHour float64) ([]models.DerbyTaskEvaluation, error) {
+	evaluations := make([]models.DerbyTaskEvaluation, 0, len(tasks))
+	for _, task := range tasks {
+		if !isKnownTaskType(task.Type) {
+			return nil, base.ErrUnknownDerbyTaskType
+		}
+		if task.Amount <= 0 {
+			return nil, base.ErrInvalidQuantity
+		}
+		evaluations = append(evaluations, e.Evaluate(task, minPointsPerHour))
+	}
+
+	sort.SliceStable(evaluations, func(i, j int) bool {
+		if evaluations[i].Instant != evaluations[j].Instant {
+			return evaluations[i].Instant
+		}
+		return evaluations[i].PointsPerHour > evaluations[j].PointsPerHour
+	})
+	return evaluations, nil
+}
+
+func (e *TaskEstimator) Evaluate(task models.DerbyTask, minPointsPerHour float64) models.DerbyTaskEvaluation {
+	evaluation := models.DerbyTaskEvaluation{Task: task}
+
+	amount := task.Amount
+	if task.Type == models.DerbySellGoods && task.Good != "" {
+		amount -= e.state.Inventory[task.Good]
+	}
+
+	// Everything is already in storage, there is no rate to compare
+	if amount <= 0 {
+		evaluation.Good = task.Good
+		evaluation.Instant = true
+		evaluation.Accept = true
+		evaluation.Reason = "done straight from storage"
+		return evaluation
+	}
+
+	candidates := e.candidates(task)
+	if len(candidates) == 0 {
+		evaluation.Reason = "no unlocked good can complete this task"
+		return evaluation
+	}
+
+	missing := ""
+	for _, good := range candidates {
+		completion, source, doable := e.completionTime(good, amount)
+		if !doable {
+			missing = source
+			continue
+		}
+		if evaluation.Good == "" || completion < evaluation.CompletionTime {
+			evaluation.Good = good.Name
+			evaluation.CompletionTime = completion
+		}
+	}
+	if evaluation.Good == "" {
+		evaluation.Reason = fmt.Sprintf("needs a %s, which the farm does not have", missing)
+		return evaluation
+	}
+
+	if evaluation.CompletionTime > 0 {
+		evaluation.PointsPerHour = float64(task.Points) / evaluation.CompletionTime.Hours()
+	}
+
+	limit := time.Duration(task.TimeLimitHours) * time.Hour
+	switch {
+	case task.TimeLimitHours > 0 && evaluation.CompletionTime > limit:
+		evaluation.Reason = fmt.Sprintf("needs %s but only %s are allowed", evaluation.CompletionTime, limit)
+	case evaluation.PointsPerHour < minPointsPerHour:
+		evaluation.Reason = fmt.Sprintf("earns %.1f points per hour, below %.1f", evaluation.PointsPerHour, minPointsPerHour)
+	default:
+		evaluation.Accept = true
+		evaluation.Reason = fmt.Sprintf("done in %s with %s", evaluation.CompletionTime, evaluation.Good)
+	}
+
+	return evaluation
+}
+
+func isKnownTaskType(taskType models.DerbyTaskType) bool {
+	switch taskType {
+	case models.DerbyProduceFromSource, models.DerbyProduceGood, models.DerbySellGoods:
+		return true
+	}
+	return false
+}
+
+func (e *TaskEstimator) candidates(task models.DerbyTask) models.HayDayGoodList {
+	var result models.HayDayGoodList
+	for _, good := range e.availableGoods {
+		if good.ProductionTime <= 0 {
+			continue
+		}
+
+		switch task.Type {
+		case models.DerbyProduceFromSource:
+			if good.Source == task.Source {
+				result = append(result, good)
+			}
+		case models.DerbyProduceGood, models.DerbySellGoods:
+			if task.Good == "" || good.Name == task.Good {
+				result = append(result, good)
+			}
+		}
+	}
+	return result
+}
+
+// Production runs on all sources of the ingredient chain in parallel, so the
+// task takes as long as its busiest source or its lead time, whichever is
+// longer. A chain that needs a source without lines cannot be done, the
+// source is returned instead.
+func (e *TaskEstimator) completionTime(good models.HayDayGood, amount int) (time.Duration, string, bool) {
+	bom := e.expander.Expand(good, amount)
+
+	sources := make([]string, 0, len(bom.MachineTime))
+	for source := range bom.MachineTime {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	completion := bom.LeadTime
+	for _, source := range sources {
+		lines := e.lines(source)
+		if lines <= 0 {
+			return 0, source, false
+		}
+		completion = max(completion, bom.MachineTime[source]/time.Duration(lines))
+	}
+	return completion, "", true
+}
+
+func (e *TaskEstimator) lines(source string) int {
+	if e.state.Capacity == nil {
+		return models.DefaultSourceCount(e.state.Level, source)
+	}
+	return e.state.Capacity[source]
+}
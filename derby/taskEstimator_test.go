@@ -0,0 +1,92 @@
+package derby
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noTirT/hayday-optimizer/base"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+// Bread takes 5 minutes in the bakery and 2 minutes of field time per unit
+type breadExpander struct{}
+
+func (breadExpander) Expand(good models.HayDayGood, quantity int) models.BillOfMaterials {
+	return models.BillOfMaterials{
+		Good:     good.Name,
+		Quantity: quantity,
+		MachineTime: map[string]time.Duration{
+			"Bakery":           time.Duration(quantity) * 5 * time.Minute,
+			models.SourceField: time.Duration(quantity) * 2 * time.Minute,
+		},
+		LeadTime: 7 * time.Minute,
+	}
+}
+
+var testBread = models.HayDayGood{Name: "Bread", Source: "Bakery", ProductionTime: 5 * time.Minute}
+
+func TestEvaluateAllRejectsInvalidTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		task models.DerbyTask
+		want error
+	}{
+		{name: "unknown type", task: models.DerbyTask{Type: "bake", Amount: 1}, want: base.ErrUnknownDerbyTaskType},
+		{name: "zero amount", task: models.DerbyTask{Type: models.DerbyProduceGood, Good: "Bread"}, want: base.ErrInvalidQuantity},
+		{name: "negative amount", task: models.DerbyTask{Type: models.DerbySellGoods, Good: "Bread", Amount: -3}, want: base.ErrInvalidQuantity},
+	}
+
+	estimator := NewTaskEstimator(models.HayDayGoodList{testBread}, breadExpander{}, models.PlayerState{Level: 10})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := estimator.EvaluateAll([]models.DerbyTask{test.task}, 0); !errors.Is(err, test.want) {
+				t.Errorf("err = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      models.PlayerState
+		task       models.DerbyTask
+		accept     bool
+		instant    bool
+		completion time.Duration
+	}{
+		{
+			name:       "typical farm",
+			state:      models.PlayerState{Level: 10},
+			task:       models.DerbyTask{Type: models.DerbyProduceGood, Good: "Bread", Amount: 6, Points: 100},
+			accept:     true,
+			completion: 30 * time.Minute,
+		},
+		{
+			name:    "sold from storage",
+			state:   models.PlayerState{Level: 10, Inventory: map[string]int{"Bread": 6}},
+			task:    models.DerbyTask{Type: models.DerbySellGoods, Good: "Bread", Amount: 6, Points: 100},
+			accept:  true,
+			instant: true,
+		},
+		{
+			name:  "ingredient source not owned",
+			state: models.PlayerState{Level: 10, Capacity: map[string]int{"Bakery": 1}},
+			task:  models.DerbyTask{Type: models.DerbyProduceGood, Good: "Bread", Amount: 6, Points: 100},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			evaluation := NewTaskEstimator(models.HayDayGoodList{testBread}, breadExpander{}, test.state).Evaluate(test.task, 0)
+
+			if evaluation.Accept != test.accept || evaluation.Instant != test.instant {
+				t.Errorf("accept = %v, instant = %v, want %v and %v (%s)", evaluation.Accept, evaluation.Instant, test.accept, test.instant, evaluation.Reason)
+			}
+			if evaluation.CompletionTime != test.completion {
+				t.Errorf("completion = %s, want %s", evaluation.CompletionTime, test.completion)
+			}
+		})
+	}
+}
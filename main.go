@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/chromedp/chromedp"
 	"github.com/noTirT/hayday-optimizer/api"
@@ -16,6 +17,12 @@ import (
 
 func main() {
 	fetch := flag.Bool("fetch", false, "Re-Fetch data from the website")
+	level := flag.Int("level", 1, "Player level used by the command line planners")
+	session := flag.Duration("session", 0, "Print a to-do list for a play session of this length and exit")
+	offline := flag.Duration("offline", 8*time.Hour, "Time away from the game after the session")
+	schedule := flag.Bool("schedule", false, "Print the production schedule of a strategy as a chart and exit")
+	strategyName := flag.String("strategy", "greedy", "Strategy used by the command line schedule")
+	hours := flag.Int("hours", 24, "Planning horizon in hours for the command line schedule")
 	flag.Parse()
 
 	hayDayFilemanager, err := base.NewJsonFileManager[models.HayDayGoodList]("./data")
@@ -27,6 +34,22 @@ func main() {
 		fetchGoods(hayDayFilemanager)
 	}
 
+	goodsRepository := api.NewGoodsRepository(hayDayFilemanager)
+	strategies := api.NewDefaultStrategyRegistry(goodsRepository)
+
+	if *session > 0 {
+		printSessionPlan(goodsRepository, *level, *session, *offline)
+		return
+	}
+
+	if *schedule {
+		state := models.PlayerState{Level: *level, Horizon: time.Duration(*hours) * time.Hour}
+		if err := printSchedule(goodsRepository, strategies, *strategyName, state); err != nil {
+			log.Fatalf("Scheduling failed: %v", err)
+		}
+		return
+	}
+
 	apiIP := "localhost"
 	apiPort := 5000
 	serverAddr := fmt.Sprintf("%s:%d", apiIP, apiPort)
@@ -35,10 +58,36 @@ func main() {
 
 	r := http.NewServeMux()
 
-	goodsRepository := api.NewGoodsRepository(hayDayFilemanager)
-	goodsController := api.NewGoodsController(goodsRepository)
+	goodsController := api.NewGoodsController(goodsRepository, strategies)
 	goodsController.Init(r)
 
+	profileFilemanager, err := base.NewJsonFileManager[models.PlayerProfileList]("./data")
+	if err != nil {
+		log.Fatalf("Failed to create file manager: %v", err)
+	}
+
+	profileRepository, err := api.NewProfileRepository(profileFilemanager)
+	if err != nil {
+		log.Fatalf("Failed to load profiles: %v", err)
+	}
+	profileController := api.NewProfileController(profileRepository, goodsRepository, strategies)
+	profileController.Init(r)
+
+	orderController := api.NewOrderController(goodsRepository, profileRepository)
+	orderController.Init(r)
+
+	shopController := api.NewShopController(goodsRepository)
+	shopController.Init(r)
+
+	derbyController := api.NewDerbyController(goodsRepository, profileRepository)
+	derbyController.Init(r)
+
+	upgradeController := api.NewUpgradeController(goodsRepository, profileRepository, strategies)
+	upgradeController.Init(r)
+
+	farmController := api.NewFarmController(goodsRepository, profileRepository, strategies)
+	farmController.Init(r)
+
 	log.Println("API server started")
 
 	log.Fatal(http.ListenAndServe(serverAddr, r))
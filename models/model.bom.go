@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+type BOMItem struct {
+	Name          string
+	Source        string
+	Amount        int
+	IsBaseProduct bool
+}
+
+// Everything needed to produce a quantity of a good from scratch
+type BillOfMaterials struct {
+	Good     string
+	Quantity int
+	Items    []BOMItem
+	// Includes the production of the target good itself
+	MachineTime map[string]time.Duration
+	TotalXP     int
+	LeadTime    time.Duration
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type DerbyTaskType string
+
+const (
+	// Produce any N goods on a given source
+	DerbyProduceFromSource DerbyTaskType = "produce_from_source"
+	// Produce N units of a given good
+	DerbyProduceGood DerbyTaskType = "produce_good"
+	// Sell N units of a given good, or of any good when none is named
+	DerbySellGoods DerbyTaskType = "sell_goods"
+)
+
+type DerbyTask struct {
+	Type           DerbyTaskType
+	Source         string
+	Good           string
+	Amount         int
+	Points         int
+	TimeLimitHours int
+}
+
+type DerbyRequest struct {
+	ProfileID string
+	Level     int
+	Tasks     []DerbyTask
+	// Goods already in the barn and silo count towards sell tasks
+	Inventory map[string]int
+	// Tasks earning fewer points per hour are declined
+	MinPointsPerHour float64
+}
+
+type DerbyTaskEvaluation struct {
+	Task DerbyTask
+	// Good the estimate is based on, the fastest one when the task allows several
+	Good           string
+	CompletionTime time.Duration
+	PointsPerHour  float64
+	// Storage already holds everything the task asks for
+	Instant bool
+	Accept  bool
+	Reason  string
+}
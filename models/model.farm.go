@@ -0,0 +1,185 @@
+package models
+
+import "time"
+
+type FieldRotationRequest struct {
+	// Plan for the farm of a profile instead of every source at Level
+	ProfileID string
+	Level     int
+	Fields    int
+	Hours     int
+	// Strategy whose plan decides what the machines need
+	Strategy string
+}
+
+// How many fields grow one crop over the horizon
+type CropAllocation struct {
+	Crop   string
+	Fields int
+	// Harvests one field gets in over the horizon
+	HarvestsPerField int
+	// Crops left after replanting
+	Output int
+	// Crops the machines of the plan consume over the horizon
+	Demand  int
+	ForSale int
+}
+
+type FieldRotationPlan struct {
+	Fields   int
+	Horizon  time.Duration
+	Strategy string
+	Crops    []CropAllocation
+	// Crops the machines need that the fields cannot grow in time
+	UnmetDemand  map[string]int `json:",omitempty"`
+	UnusedFields int
+	// Sale value of the crops left over after the machines are served
+	SaleCoins int
+}
+
+type FeedBalanceRequest struct {
+	// Take animals and feed mills from a profile instead of the request
+	ProfileID string
+	Level     int
+	// Animals per source name, e.g. "Chicken" or "Cow"
+	Animals map[string]int
+	// Left out, the profile's Feed Mills or those of a typical farm count
+	FeedMills *int
+	Hours     int
+}
+
+// All animals of one kind and what keeping them fed costs
+type AnimalPen struct {
+	Animal     string
+	Count      int
+	Good       string
+	Output     int
+	Feed       string
+	FeedNeeded int
+	// Sale value of the output
+	GrossValue int
+	// Sale value of the crops that went into the feed
+	FeedCost float64
+	Profit   float64
+	// Profit of one unit of the good once its feed is paid for
+	UnitProfit    float64
+	ProfitPerHour float64
+}
+
+type FeedSupply struct {
+	Feed        string
+	Units       int
+	Batches     int
+	MachineTime time.Duration
+}
+
+type CropInput struct {
+	Crop   string
+	Amount int
+	// Fields needed to grow the amount within the horizon
+	Fields int
+}
+
+type FeedBalance struct {
+	Horizon time.Duration
+	Pens    []AnimalPen
+	Feed    []FeedSupply
+	// Feed Mills needed to keep every animal fed
+	FeedMillsNeeded int
+	FeedMills       int
+	// Share of the needed feed the owned Feed Mills can make, 1 when no
+	// feed is needed
+	FeedMillCoverage float64
+	Crops            []CropInput
+	FieldsNeeded     int
+	Profit           float64
+	ProfitPerHour    float64
+}
+
+// Harvests a tree or bush gives in the game before it dies
+const DefaultTreeHarvests = 3
+
+// How a tree or bush lives. The dataset has none of this, values the request
+// leaves out fall back to defaults. A replant cost of 0 is a free replant.
+type TreeLifecycle struct {
+	// Harvests before the plant dies
+	Harvests *int `json:",omitempty"`
+	// Coins to clear a dead plant and put in a new one
+	ReplantCost *int `json:",omitempty"`
+	// Left out, the production time of the dataset applies
+	RegrowthHours *float64 `json:",omitempty"`
+}
+
+// A plant gives at least one harvest, takes time to regrow and costs nothing
+// or more to replant
+func (l TreeLifecycle) Valid() bool {
+	return (l.Harvests == nil || *l.Harvests > 0) &&
+		(l.ReplantCost == nil || *l.ReplantCost >= 0) &&
+		(l.RegrowthHours == nil || *l.RegrowthHours > 0)
+}
+
+// Fill in harvests and replant cost for a plant growing the good when they
+// were left out, a new plant is assumed to cost about what one harvest sells
+// for. Reports whether any default was used.
+func (l TreeLifecycle) WithDefaults(good HayDayGood) (TreeLifecycle, bool) {
+	assumed := false
+	if l.Harvests == nil {
+		harvests := DefaultTreeHarvests
+		l.Harvests = &harvests
+		assumed = true
+	}
+	if l.ReplantCost == nil {
+		replantCost := good.MaxPrice
+		l.ReplantCost = &replantCost
+		assumed = true
+	}
+	return l, assumed
+}
+
+// Share of the replant cost every harvest has to pay off, only for a
+// lifecycle with its defaults filled in
+func (l TreeLifecycle) ReplantCostPerHarvest() float64 {
+	return float64(*l.ReplantCost) / float64(*l.Harvests)
+}
+
+type OrchardRequest struct {
+	// Take level and owned trees and bushes from a profile
+	ProfileID string
+	Level     int
+	// Spots available for trees and bushes, defaults to what the profile owns
+	Plots int
+	Hours int
+	// Strategy whose plan decides which fruits the machines need
+	Strategy   string
+	Lifecycles map[string]TreeLifecycle
+}
+
+type TreeValuation struct {
+	Source      string
+	Good        string
+	Harvests    int
+	ReplantCost int
+	Regrowth    time.Duration
+	// True when harvests or replant cost were not given and the defaults apply
+	AssumedLifecycle bool
+	// Sale value of all harvests of one plant minus its replant cost
+	LifetimeProfit int
+	// Lifetime profit spread over the time the plant lives
+	AmortisedProfitPerHour float64
+	// Fruit the machines of the plan consume over the horizon
+	Demand      int
+	Owned       int
+	Recommended int
+}
+
+type OrchardPlan struct {
+	Horizon  time.Duration
+	Plots    int
+	Strategy string
+	Trees    []TreeValuation
+	// Sources without a known regrowth time, they cannot be valued
+	Unvalued    []string `json:",omitempty"`
+	UnusedPlots int
+	// Profit of the recommended trees and bushes over the horizon
+	Profit float64
+}
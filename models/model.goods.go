@@ -35,3 +35,23 @@ type RawTableRow struct {
 }
 
 type HayDayGoodList []HayDayGood
+
+// A good together with values derived from its ingredient chain
+type GoodDetails struct {
+	HayDayGood
+	LeadTime time.Duration
+}
+
+// A good selected by the optimizer together with its valuation
+type PlannedGood struct {
+	HayDayGood
+	GrossValue      int
+	NetValue        int
+	XPPerHour       float64
+	XPPerIngredient float64
+	// Units to produce over the planning horizon, every strategy sizes its
+	// plan to keep its sources busy for that long
+	Quantity int `json:",omitempty"`
+}
+
+type PlannedGoodList []PlannedGood
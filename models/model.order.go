@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+type OrderItem struct {
+	Name   string
+	Amount int
+}
+
+type TruckOrder struct {
+	// Level of the player, goods above it cannot be made
+	Level int
+	Goods []OrderItem
+	Coins int
+	XP    int
+	// Optional coin value of one experience point
+	CoinsPerXP float64
+}
+
+type OrderRecommendation string
+
+const (
+	RecommendFill    OrderRecommendation = "fill"
+	RecommendSkip    OrderRecommendation = "skip"
+	RecommendRefresh OrderRecommendation = "refresh"
+)
+
+type TruckOrderEvaluation struct {
+	Recommendation OrderRecommendation
+	// Coins the requested goods would fetch when sold instead
+	SaleValue int
+	// Machine time per source to make the goods again from scratch
+	ReplacementTime map[string]time.Duration
+	// Coins the sources could have earned during that machine time
+	ReplacementCost int
+	OpportunityCost int
+	RewardValue     int
+	Balance         int
+	// Requested goods the player has not unlocked yet
+	LockedGoods []string
+}
+
+// One crate of a boat order holds a fixed amount of a single good
+type BoatCrate struct {
+	Good   string
+	Amount int
+}
+
+type BoatOrder struct {
+	// Plan with the farm of this profile instead of everything unlocked at Level
+	ProfileID  string
+	Level      int
+	Crates     []BoatCrate
+	Deadline   time.Time
+	Coins      int
+	XP         int
+	CoinsPerXP float64
+	// Goods already in the barn and silo, by name
+	Inventory map[string]int
+}
+
+type CratePlan struct {
+	Good    string
+	Amount  int
+	Ready   bool
+	ReadyAt *time.Time `json:",omitempty"`
+}
+
+type BoatOrderPlan struct {
+	CanComplete bool
+	// Crates in the order they can be filled
+	Crates      []CratePlan
+	Schedule    ProductionSchedule
+	RewardValue int
+	// Coins the same goods would fetch when sold instead
+	SaleValue int
+	Balance   int
+}
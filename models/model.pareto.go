@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// How much the player cares about each objective. Only the ratio between the
+// weights matters.
+type ObjectiveWeights struct {
+	Coins float64
+	XP    float64
+	Time  float64
+}
+
+// A production mix that no other mix beats on every objective at once
+type ParetoOption struct {
+	Goods        PlannedGoodList
+	CoinsPerHour float64
+	XPPerHour    float64
+	// Longest production time in the mix, lower is better
+	CycleTime time.Duration
+	// Weights the mix was found with
+	Weights ObjectiveWeights
+}
+
+type ParetoFrontier struct {
+	Level   int
+	Options []ParetoOption
+	// Only filled in when the request included objective weights
+	Weights     *ObjectiveWeights `json:",omitempty"`
+	Recommended *ParetoOption     `json:",omitempty"`
+}
@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// What the player brings to a planning request
+type PlayerState struct {
+	Level int
+	// Experience still missing to reach the level the player is aiming for
+	XPToTarget int
+	// Level the player is aiming for, used with XPToNextLevel when XPToTarget
+	// is not given
+	TargetLevel int
+	// Experience needed to get from a level to the next one, keyed by level
+	XPToNextLevel map[int]int
+	// How far ahead the strategy may plan production
+	Horizon time.Duration
+	// How often the player collects and restarts production, see JobCycle
+	VisitInterval time.Duration
+	// Parallel production lines per source. When set only these sources are
+	// available, otherwise every source unlocked at the level has the lines
+	// of a typical farm, see DefaultSourceCount.
+	Capacity   map[string]int
+	QueueSlots map[string]int
+	// Goods already in the barn and silo, by name
+	Inventory    map[string]int
+	BarnCapacity int
+	SiloCapacity int
+	// Tree and bush lifecycles by source, the rest use the defaults
+	Lifecycles map[string]TreeLifecycle
+	// Return the optimizer's decision log with the plan
+	Explain bool
+}
+
+// Body of a planning request that carries the player's current stock
+type StrategyRequest struct {
+	Strategy      string
+	Level         int
+	XPToTarget    int
+	TargetLevel   int
+	XPToNextLevel map[int]int
+	Hours         int
+	VisitMinutes  int
+	Inventory     map[string]int
+	BarnCapacity  int
+	SiloCapacity  int
+	Explain       bool
+}
+
+// How much of an ingredient the plan consumes and where it comes from
+type IngredientNeed struct {
+	Name          string
+	Needed        int
+	FromInventory int
+	ToProduce     int
+}
+
+type StorageUsage struct {
+	Storage  string
+	Capacity int
+	Used     int
+	Overflow bool
+}
+
+// Result of running a strategy
+type Plan struct {
+	Strategy string
+	Goods    PlannedGoodList
+	// Estimated earnings while all planned goods are in production
+	CoinsPerHour float64
+	// Only set by strategies that search for the best plan, false when the
+	// search gave up before proving it
+	Optimal *bool `json:",omitempty"`
+	// Only filled in by strategies that optimize for experience
+	XPPerHour     float64 `json:",omitempty"`
+	HoursToTarget float64 `json:",omitempty"`
+	// Only filled in when the request included an inventory
+	Ingredients []IngredientNeed `json:",omitempty"`
+	Storage     []StorageUsage   `json:",omitempty"`
+	// Only filled in when an explanation was requested
+	Decisions []PlanDecision `json:",omitempty"`
+}
+
+type PlanRule string
+
+const (
+	RuleNotBestOfSource          PlanRule = "not_best_of_source"
+	RuleBaseProductInChain       PlanRule = "base_product_in_chain"
+	RuleIngredientOfHigherRanked PlanRule = "ingredient_of_higher_ranked"
+	RuleSourceConflict           PlanRule = "source_conflict"
+	RuleNotProfitable            PlanRule = "not_profitable"
+)
+
+// Why the optimizer dropped a good from the plan
+type PlanDecision struct {
+	Good string
+	Rule PlanRule
+	// Higher ranked good responsible for the removal
+	CausedBy string `json:",omitempty"`
+	// Source the conflict happened on
+	Source string `json:",omitempty"`
+}
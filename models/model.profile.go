@@ -0,0 +1,22 @@
+package models
+
+import "github.com/google/uuid"
+
+// What a farmer actually owns, used to plan for their farm instead of every
+// source unlocked at their level
+type PlayerProfile struct {
+	ID    uuid.UUID
+	Name  string
+	Level int
+	// Production buildings by source name
+	OwnedSources []string
+	// Queue slots per machine, machines not listed have the default amount
+	QueueSlots map[string]int
+	Fields     int
+	// Counts per source name, e.g. "Apple tree", "Raspberry bush" or "Chicken"
+	Trees   map[string]int
+	Bushes  map[string]int
+	Animals map[string]int
+}
+
+type PlayerProfileList []PlayerProfile
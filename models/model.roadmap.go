@@ -0,0 +1,34 @@
+package models
+
+type RoadmapRequest struct {
+	From     int
+	To       int
+	Strategy string
+	// Experience needed to get from a level to the next one, keyed by level.
+	// Either every level of the range but the last has an entry or none does,
+	// without any the roadmap has no time estimates.
+	XPToNextLevel map[int]int
+}
+
+// What changes when the player reaches a level
+type RoadmapStep struct {
+	Level           int
+	UnlockedGoods   []string
+	UnlockedSources []string
+	// Difference to the plan of the previous level
+	AddedToPlan     []string
+	RemovedFromPlan []string
+	CoinsPerHour    float64
+	// Rate of the xp strategy at this level
+	XPPerHour        float64
+	HoursToNextLevel float64 `json:",omitempty"`
+}
+
+type LevelRoadmap struct {
+	From     int
+	To       int
+	Strategy string
+	Steps    []RoadmapStep
+	// Hours from the first to the last level of the range
+	TotalHours float64 `json:",omitempty"`
+}
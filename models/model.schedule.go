@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+type ScheduledJob struct {
+	Good string
+	// Units the job hands out, more than one for sources that make batches
+	Amount int
+	// Earliest moment the job fits the line's queue and its ingredients are
+	// done. Queueing it any time before Start keeps the line busy.
+	QueueFrom time.Time
+	Start     time.Time
+	Finish    time.Time
+}
+
+// Jobs of one production slot of a source in the order they run
+type SlotTimeline struct {
+	Source string
+	Slot   int
+	Jobs   []ScheduledJob
+	Busy   time.Duration
+}
+
+type ProductionSchedule struct {
+	Start     time.Time
+	Horizon   time.Duration
+	Timelines []SlotTimeline
+	// Units that could not finish within the horizon, by good name
+	Unscheduled map[string]int
+	// Units of goods without a known production time the plan takes from
+	// outside the lines, mined, fished or picked, by good name
+	External map[string]int
+}
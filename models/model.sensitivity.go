@@ -0,0 +1,51 @@
+package models
+
+type PerturbedField string
+
+const (
+	FieldMaxPrice       PerturbedField = "MaxPrice"
+	FieldProductionTime PerturbedField = "ProductionTime"
+)
+
+// A change to a single value of a single good, Percent is signed
+type Perturbation struct {
+	Good    string
+	Field   PerturbedField
+	Percent float64
+}
+
+// Whether a good of the original plan survives every perturbation
+type RecommendationStability struct {
+	Good   string
+	Source string
+	// The good's own price and production time were perturbed, a good that
+	// was not tested is never reported as stable
+	Tested bool
+	Stable bool
+	// Perturbations after which the good was no longer recommended
+	DroppedBy []Perturbation `json:",omitempty"`
+}
+
+// A good that only shows up in the plan after a perturbation
+type PlanEntrant struct {
+	Good    string
+	Source  string
+	AddedBy []Perturbation
+}
+
+type SensitivityReport struct {
+	Strategy string
+	Level    int
+	Percent  float64
+	// Number of perturbed plans the original was compared against
+	Scenarios int
+	// Perturbations left out to keep the analysis short
+	Skipped int
+	// The time budget ran out before the scenario limit was reached
+	OutOfTime       bool
+	Recommendations []RecommendationStability
+	Entrants        []PlanEntrant
+	// Range of plan earnings across all scenarios
+	MinCoinsPerHour float64
+	MaxCoinsPerHour float64
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// One entry of the to-do list for a play session. Times are offsets from the
+// moment the session starts.
+type SessionTask struct {
+	Source  string
+	Line    int
+	Good    string
+	StartAt time.Duration
+	ReadyAt time.Duration
+	// Runs through the offline gap and is only collected on the next session
+	CollectAfterGap bool
+}
+
+type SessionPlan struct {
+	Session    time.Duration
+	OfflineGap time.Duration
+	Tasks      []SessionTask
+	Coins      int
+	XP         int
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type ShopRequest struct {
+	Inventory map[string]int
+	Slots     int
+	// How often a slot gets relisted, defaults to once a day
+	RelistHours int
+}
+
+type ShopListing struct {
+	Good     string
+	Quantity int
+	// Asking price for the whole stack
+	Price int
+	// Machine time to make the stack again
+	RestockTime time.Duration
+	CoinsPerDay float64
+}
+
+type ShopPlan struct {
+	Slots       int
+	Listings    []ShopListing
+	CoinsPerDay float64
+	UnusedSlots int
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type SimulationSetup struct {
+	Horizon time.Duration
+	// Parallel production lines per source, sources not listed have one
+	Capacity map[string]int
+	// Jobs a line holds at once and how often the player empties the lines,
+	// together they decide how fast a line turns over, see JobCycle
+	QueueSlots    map[string]int
+	VisitInterval time.Duration
+	// Goods in the barn and silo at the start, by name
+	Inventory map[string]int
+	// Events the timeline keeps, later ones are only counted. A day on a big
+	// farm runs into tens of thousands of events.
+	TimelineLimit int
+}
+
+type SimulationEventKind string
+
+const (
+	EventStart   SimulationEventKind = "start"
+	EventFeed    SimulationEventKind = "feed"
+	EventFinish  SimulationEventKind = "finish"
+	EventReplant SimulationEventKind = "replant"
+	EventSell    SimulationEventKind = "sell"
+)
+
+type SimulationEvent struct {
+	At     time.Duration
+	Kind   SimulationEventKind
+	Source string
+	Line   int
+	Good   string
+	Amount int
+}
+
+type SimulationResult struct {
+	Horizon  time.Duration
+	Coins    int
+	XP       int
+	Sold     map[string]int
+	IdleTime map[string]time.Duration
+	Timeline []SimulationEvent
+	// Events of the whole run, more than the timeline holds when it was cut
+	TimelineEvents int
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	SourceField    = "Field"
+	SourceFeedMill = "Feed Mill"
+	// A harvested field gives two crops and one goes straight back into the ground
+	FieldYield = 2
+	// One set of ingredients fills the Feed Mill with a batch of three feed
+	FeedMillYield = 3
+	// How often the player comes by to collect and restart production when
+	// the request does not say
+	DefaultVisitInterval = 30 * time.Minute
+)
+
+// Typical farm assumed when the player has not said what they own
+const (
+	startingFields  = 6
+	maxFields       = 60
+	chickenCoopSize = 6
+	animalPenSize   = 5
+	orchardRowSize  = 4
+)
+
+// Sources that are animals eating feed instead of machines
+var AnimalSources = map[string]bool{
+	"Chicken": true,
+	"Cow":     true,
+	"Pig":     true,
+	"Sheep":   true,
+	"Goat":    true,
+}
+
+func IsTreeOrBushSource(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasSuffix(lower, " tree") || strings.HasSuffix(lower, " bush")
+}
+
+// Machines queue several jobs, fields, animals, trees and bushes hold one at a time
+func IsMachineSource(source string) bool {
+	return source != SourceField && !AnimalSources[source] && !IsTreeOrBushSource(source)
+}
+
+// Lines of a source a typical farm at the level has: one of every machine,
+// fields that grow with the level, full pens and a row of every tree and bush
+func DefaultSourceCount(level int, source string) int {
+	switch {
+	case source == SourceField:
+		return min(startingFields+level, maxFields)
+	case source == "Chicken":
+		return chickenCoopSize
+	case AnimalSources[source]:
+		return animalPenSize
+	case IsTreeOrBushSource(source):
+		return orchardRowSize
+	}
+	return 1
+}
+
+// Units one run of a source hands out. Only the Feed Mill makes more than one,
+// the second crop of a field goes straight back into the ground.
+func BatchYield(source string) int {
+	if source == SourceFeedMill {
+		return FeedMillYield
+	}
+	return 1
+}
+
+// Runs of a source needed for the given units
+func Batches(source string, units int) int {
+	yield := BatchYield(source)
+	return (units + yield - 1) / yield
+}
+
+// Time a line is taken by one job when the player only comes by every visit
+// interval. Finished goods wait on the line until they are collected, and
+// between visits a line only works through the jobs its queue holds.
+func JobCycle(productionTime, visitInterval time.Duration, queueSlots int) time.Duration {
+	if visitInterval <= 0 {
+		visitInterval = DefaultVisitInterval
+	}
+	return max(productionTime, visitInterval/time.Duration(max(queueSlots, 1)))
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// A building the player considers buying
+type PurchaseRequest struct {
+	ProfileID string
+	Source    string
+	// Price of the building in coins
+	Cost     int
+	Strategy string
+	Hours    int
+}
+
+type PurchaseEvaluation struct {
+	Source string
+	Cost   int
+	// Lines of the source the player had before the purchase
+	Owned             int
+	Strategy          string
+	CoinsPerDayBefore float64
+	CoinsPerDayAfter  float64
+	ExtraCoinsPerDay  float64
+	// Days until the extra earnings cover the cost, only set when they ever do
+	PaybackDays float64 `json:",omitempty"`
+	PaysBack    bool
+	// Goods the plan only makes once the source is bought
+	NewGoods []string
+}
+
+// What one more queue slot on a machine would earn
+type SlotUpgrade struct {
+	Source string
+	// Queue slots the machine has today
+	Slots            int
+	ExtraCoinsPerDay float64
+	ExtraXPPerDay    float64
+}
+
+type SlotUpgradeReport struct {
+	// Strategy whose plan the machines are valued by
+	Strategy   string
+	Session    time.Duration
+	OfflineGap time.Duration
+	// Objective the upgrades are ordered by, "coins" or "xp"
+	SortedBy string
+	Upgrades []SlotUpgrade
+}
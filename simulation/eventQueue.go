@@ -0,0 +1,42 @@
+package simulation
+
+import (
+	"container/heap"
+	"time"
+)
+
+type completion struct {
+	at     time.Duration
+	source string
+	line   int
+}
+
+// Min-heap of production completions ordered by time
+type eventQueue []completion
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	if q[i].source != q[j].source {
+		return q[i].source < q[j].source
+	}
+	return q[i].line < q[j].line
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(completion)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+func (q *eventQueue) push(c completion) { heap.Push(q, c) }
+
+func (q *eventQueue) pop() completion { return heap.Pop(q).(completion) }
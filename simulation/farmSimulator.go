@@ -0,0 +1,279 @@
+package simulation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+const defaultHorizon = 24 * time.Hour
+
+type productionLine struct {
+	good      *models.HayDayGood
+	idleSince time.Duration
+}
+
+// Discrete-event simulation of a farm producing a plan. Planned goods are sold as
+// soon as they are collected, their ingredients are produced on demand so that
+// every consumer always has one batch worth of stock waiting.
+type FarmSimulator struct {
+	goodsMap    map[uuid.UUID]models.HayDayGood
+	goodsByName map[string]models.HayDayGood
+	setup       models.SimulationSetup
+
+	now        time.Duration
+	lines      map[string][]*productionLine
+	sources    []string
+	goodsBySrc map[string]models.HayDayGoodList
+	targets    map[uuid.UUID]bool
+	buffer     map[uuid.UUID]int
+	stock      map[uuid.UUID]int
+	inProgress map[uuid.UUID]int
+	queue      eventQueue
+	result     models.SimulationResult
+}
+
+func NewFarmSimulator(allGoods models.HayDayGoodList, setup models.SimulationSetup) *FarmSimulator {
+	goodsMap := make(map[uuid.UUID]models.HayDayGood)
+	goodsByName := make(map[string]models.HayDayGood)
+	for _, good := range allGoods {
+		goodsMap[good.ID] = good
+		goodsByName[good.Name] = good
+	}
+
+	if setup.Horizon <= 0 {
+		setup.Horizon = defaultHorizon
+	}
+
+	return &FarmSimulator{
+		goodsMap:    goodsMap,
+		goodsByName: goodsByName,
+		setup:       setup,
+	}
+}
+
+func (s *FarmSimulator) Run(plan models.PlannedGoodList) models.SimulationResult {
+	s.reset(plan)
+
+	s.startIdleLines()
+	for s.queue.Len() > 0 && s.queue[0].at <= s.setup.Horizon {
+		s.now = s.queue[0].at
+		// Collect everything that finishes at the same moment before starting new work
+		for s.queue.Len() > 0 && s.queue[0].at == s.now {
+			s.finish(s.queue.pop())
+		}
+		s.startIdleLines()
+	}
+
+	for _, source := range s.sources {
+		for _, line := range s.lines[source] {
+			if line.good == nil {
+				s.result.IdleTime[source] += s.setup.Horizon - line.idleSince
+			}
+		}
+	}
+
+	return s.result
+}
+
+func (s *FarmSimulator) reset(plan models.PlannedGoodList) {
+	s.now = 0
+	s.lines = make(map[string][]*productionLine)
+	s.sources = nil
+	s.goodsBySrc = make(map[string]models.HayDayGoodList)
+	s.targets = make(map[uuid.UUID]bool)
+	s.buffer = make(map[uuid.UUID]int)
+	s.stock = make(map[uuid.UUID]int)
+	s.inProgress = make(map[uuid.UUID]int)
+	s.queue = eventQueue{}
+	s.result = models.SimulationResult{
+		Horizon:  s.setup.Horizon,
+		Sold:     make(map[string]int),
+		IdleTime: make(map[string]time.Duration),
+		Timeline: []models.SimulationEvent{},
+	}
+
+	for name, amount := range s.setup.Inventory {
+		if good, exists := s.goodsByName[name]; exists {
+			s.stock[good.ID] += amount
+		}
+	}
+
+	involved := make(map[uuid.UUID]bool)
+	for _, planned := range plan {
+		s.targets[planned.ID] = true
+		s.collectChain(planned.HayDayGood, involved)
+	}
+
+	// Targets first in plan order so the most valuable goods are preferred
+	var ordered models.HayDayGoodList
+	for _, planned := range plan {
+		ordered = append(ordered, s.goodsMap[planned.ID])
+	}
+	var ingredients models.HayDayGoodList
+	for id := range involved {
+		if !s.targets[id] {
+			ingredients = append(ingredients, s.goodsMap[id])
+		}
+	}
+	sort.Slice(ingredients, func(i, j int) bool {
+		return ingredients[i].Name < ingredients[j].Name
+	})
+	ordered = append(ordered, ingredients...)
+
+	for _, good := range ordered {
+		// Goods without a production time cannot be scheduled
+		if good.ProductionTime <= 0 {
+			continue
+		}
+		if _, exists := s.goodsBySrc[good.Source]; !exists {
+			s.sources = append(s.sources, good.Source)
+		}
+		s.goodsBySrc[good.Source] = append(s.goodsBySrc[good.Source], good)
+
+		for _, ingredient := range good.Ingredients {
+			s.buffer[ingredient.ProductID] += ingredient.Amount
+		}
+	}
+	sort.Strings(s.sources)
+
+	for _, source := range s.sources {
+		capacity := s.setup.Capacity[source]
+		if capacity < 1 {
+			capacity = 1
+		}
+		for range capacity {
+			s.lines[source] = append(s.lines[source], &productionLine{})
+		}
+	}
+}
+
+func (s *FarmSimulator) collectChain(good models.HayDayGood, involved map[uuid.UUID]bool) {
+	if involved[good.ID] {
+		return
+	}
+	involved[good.ID] = true
+
+	for _, ingredient := range good.Ingredients {
+		if ingredientGood, exists := s.goodsMap[ingredient.ProductID]; exists {
+			s.collectChain(ingredientGood, involved)
+		}
+	}
+}
+
+func (s *FarmSimulator) startIdleLines() {
+	for _, source := range s.sources {
+		for i, line := range s.lines[source] {
+			if line.good != nil {
+				continue
+			}
+			good, found := s.chooseJob(source)
+			if !found {
+				continue
+			}
+			s.start(source, i, line, good)
+		}
+	}
+}
+
+// Refill ingredients that fall short of their buffer first, then make planned goods
+func (s *FarmSimulator) chooseJob(source string) (models.HayDayGood, bool) {
+	var best models.HayDayGood
+	bestDeficit := 0
+	for _, good := range s.goodsBySrc[source] {
+		deficit := s.buffer[good.ID] - s.stock[good.ID] - s.inProgress[good.ID]
+		if deficit > bestDeficit && s.canStart(good) {
+			best = good
+			bestDeficit = deficit
+		}
+	}
+	if bestDeficit > 0 {
+		return best, true
+	}
+
+	for _, good := range s.goodsBySrc[source] {
+		if s.targets[good.ID] && s.canStart(good) {
+			return good, true
+		}
+	}
+
+	return models.HayDayGood{}, false
+}
+
+func (s *FarmSimulator) canStart(good models.HayDayGood) bool {
+	for _, ingredient := range good.Ingredients {
+		if s.stock[ingredient.ProductID] < ingredient.Amount {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *FarmSimulator) start(source string, index int, line *productionLine, good models.HayDayGood) {
+	for _, ingredient := range good.Ingredients {
+		s.stock[ingredient.ProductID] -= ingredient.Amount
+	}
+
+	s.result.IdleTime[source] += s.now - line.idleSince
+	line.good = &good
+	s.inProgress[good.ID] += models.BatchYield(good.Source)
+
+	kind := models.EventStart
+	if models.AnimalSources[source] {
+		kind = models.EventFeed
+	}
+	s.record(kind, source, index, good.Name, 1)
+
+	// The line stays taken until the player comes by to collect the job
+	cycle := models.JobCycle(good.ProductionTime, s.setup.VisitInterval, s.setup.QueueSlots[source])
+	s.queue.push(completion{at: s.now + cycle, source: source, line: index})
+}
+
+func (s *FarmSimulator) finish(done completion) {
+	line := s.lines[done.source][done.line]
+	good := *line.good
+	line.good = nil
+	line.idleSince = s.now
+
+	yield := models.BatchYield(good.Source)
+	s.inProgress[good.ID] -= yield
+	s.stock[good.ID] += yield
+	s.result.XP += good.GainedXP
+	s.record(models.EventFinish, done.source, done.line, good.Name, yield)
+
+	if good.Source == models.SourceField {
+		s.record(models.EventReplant, done.source, done.line, good.Name, models.FieldYield-1)
+	}
+
+	if !s.targets[good.ID] {
+		return
+	}
+
+	// Keep what downstream recipes are waiting for and sell the rest
+	surplus := s.stock[good.ID] - s.buffer[good.ID]
+	if surplus <= 0 {
+		return
+	}
+	s.stock[good.ID] -= surplus
+	s.result.Coins += surplus * good.MaxPrice
+	s.result.Sold[good.Name] += surplus
+	s.record(models.EventSell, done.source, done.line, good.Name, surplus)
+}
+
+func (s *FarmSimulator) record(kind models.SimulationEventKind, source string, line int, good string, amount int) {
+	s.result.TimelineEvents++
+	if len(s.result.Timeline) >= s.setup.TimelineLimit {
+		return
+	}
+
+	s.result.Timeline = append(s.result.Timeline, models.SimulationEvent{
+		At:     s.now,
+		Kind:   kind,
+		Source: source,
+		Line:   line,
+		Good:   good,
+		Amount: amount,
+	})
+}
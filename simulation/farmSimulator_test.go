@@ -0,0 +1,60 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noTirT/hayday-optimizer/models"
+)
+
+func wheatPlan() (models.HayDayGoodList, models.PlannedGoodList) {
+	wheat := models.HayDayGood{
+		ID:             uuid.NewSHA1(uuid.NameSpaceOID, []byte("Wheat")),
+		Name:           "Wheat",
+		RequiredLevel:  1,
+		MaxPrice:       3,
+		ProductionTime: 2 * time.Minute,
+		GainedXP:       1,
+		Source:         models.SourceField,
+	}
+	return models.HayDayGoodList{wheat}, models.PlannedGoodList{{HayDayGood: wheat}}
+}
+
+func TestFarmSimulatorTimelineLimit(t *testing.T) {
+	// Two fields planted at every visit for two hours and harvested at the
+	// four after the first, each harvest replanted and sold: 34 events
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "left out", limit: 0, want: 0},
+		{name: "cut", limit: 5, want: 5},
+		{name: "all", limit: 1000, want: 34},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			goods, plan := wheatPlan()
+			simulator := NewFarmSimulator(goods, models.SimulationSetup{
+				Horizon:       2 * time.Hour,
+				Capacity:      map[string]int{models.SourceField: 2},
+				VisitInterval: 30 * time.Minute,
+				TimelineLimit: test.limit,
+			})
+
+			result := simulator.Run(plan)
+
+			if len(result.Timeline) != test.want {
+				t.Errorf("timeline = %d events, want %d", len(result.Timeline), test.want)
+			}
+			if result.TimelineEvents != 34 {
+				t.Errorf("timeline events = %d, want 34", result.TimelineEvents)
+			}
+			if result.Sold["Wheat"] != 8 {
+				t.Errorf("sold = %d wheat, want 8 whatever the timeline keeps", result.Sold["Wheat"])
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package solver
+
+import (
+	"context"
+	"math"
+
+	"github.com/noTirT/hayday-optimizer/base"
+)
+
+const integerTolerance = 1e-6
+
+type node struct {
+	bounds []Constraint
+}
+
+// Solve the problem with every variable restricted to whole numbers.
+// The search stops early once maxNodes LPs were solved or the context is done,
+// in that case the best solution found so far is returned as not optimal.
+func SolveMILP(ctx context.Context, problem Problem, maxNodes int) (Solution, error) {
+	incumbent, hasIncumbent := zeroSolution(problem)
+
+	stack := []node{{}}
+	explored := 0
+	exhausted := true
+
+	for len(stack) > 0 {
+		if explored >= maxNodes || ctx.Err() != nil {
+			exhausted = false
+			break
+		}
+
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		explored++
+
+		relaxed := Problem{
+			Objective:   problem.Objective,
+			Constraints: append(problem.Constraints[:len(problem.Constraints):len(problem.Constraints)], current.bounds...),
+		}
+
+		solution, err := SolveLP(relaxed)
+		if err != nil {
+			// Infeasible branches are simply pruned, anything else on the root is fatal
+			if explored == 1 {
+				return Solution{}, err
+			}
+			continue
+		}
+
+		if hasIncumbent && solution.Objective <= incumbent.Objective+integerTolerance {
+			continue
+		}
+
+		branchVariable := mostFractional(solution.Values)
+		if branchVariable == -1 {
+			incumbent = roundSolution(solution)
+			hasIncumbent = true
+			continue
+		}
+
+		value := solution.Values[branchVariable]
+		down := node{bounds: withBound(current.bounds, branchVariable, len(problem.Objective), 1, math.Floor(value))}
+		up := node{bounds: withBound(current.bounds, branchVariable, len(problem.Objective), -1, -math.Ceil(value))}
+
+		// Explore the side closer to the relaxed value first
+		if value-math.Floor(value) < 0.5 {
+			stack = append(stack, up, down)
+		} else {
+			stack = append(stack, down, up)
+		}
+	}
+
+	if !hasIncumbent {
+		return Solution{}, base.ErrNoIntegerSolution
+	}
+
+	incumbent.Optimal = exhausted
+	return incumbent, nil
+}
+
+// The origin is a valid starting incumbent whenever it satisfies every constraint
+func zeroSolution(problem Problem) (Solution, bool) {
+	for _, constraint := range problem.Constraints {
+		if constraint.Bound < 0 {
+			return Solution{}, false
+		}
+	}
+	return Solution{Values: make([]float64, len(problem.Objective))}, true
+}
+
+func mostFractional(values []float64) int {
+	best := -1
+	bestDistance := integerTolerance
+	for j, value := range values {
+		distance := math.Abs(value - math.Round(value))
+		if distance > bestDistance {
+			best = j
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+func roundSolution(solution Solution) Solution {
+	values := make([]float64, len(solution.Values))
+	for j, value := range solution.Values {
+		values[j] = math.Round(value)
+	}
+	solution.Values = values
+	return solution
+}
+
+func withBound(bounds []Constraint, variable, variables int, sign, bound float64) []Constraint {
+	coefficients := make([]float64, variables)
+	coefficients[variable] = sign
+
+	result := make([]Constraint, len(bounds), len(bounds)+1)
+	copy(result, bounds)
+	return append(result, Constraint{Coefficients: coefficients, Bound: bound})
+}
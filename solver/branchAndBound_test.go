@@ -0,0 +1,71 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+)
+
+// Relaxation peaks at x = 3, y = 1.5 with 21, the best whole numbers are x = 4, y = 0
+func fractionalProblem() Problem {
+	return Problem{
+		Objective: []float64{5, 4},
+		Constraints: []Constraint{
+			{Coefficients: []float64{6, 4}, Bound: 24},
+			{Coefficients: []float64{1, 2}, Bound: 6},
+		},
+	}
+}
+
+func TestSolveMILPKnownOptimum(t *testing.T) {
+	solution, err := SolveMILP(context.Background(), fractionalProblem(), 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertValues(t, solution, 20, []float64{4, 0})
+	if !solution.Optimal {
+		t.Error("exhausted search not marked optimal")
+	}
+}
+
+func TestSolveMILPNodeLimit(t *testing.T) {
+	solution, err := SolveMILP(context.Background(), fractionalProblem(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if solution.Optimal {
+		t.Error("search cut off by the node limit marked optimal")
+	}
+	// Only the root was solved, the origin is the best whole solution known
+	assertValues(t, solution, 0, []float64{0, 0})
+}
+
+func TestSolveMILPCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	solution, err := SolveMILP(ctx, fractionalProblem(), 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if solution.Optimal {
+		t.Error("cancelled search marked optimal")
+	}
+}
+
+func TestSolveMILPInfeasible(t *testing.T) {
+	_, err := SolveMILP(context.Background(), Problem{
+		Objective: []float64{1},
+		Constraints: []Constraint{
+			{Coefficients: []float64{1}, Bound: 1},
+			{Coefficients: []float64{-1}, Bound: -2},
+		},
+	}, 1000)
+	if !errors.Is(err, base.ErrProblemInfeasible) {
+		t.Fatalf("err = %v, want %v", err, base.ErrProblemInfeasible)
+	}
+}
@@ -0,0 +1,256 @@
+package solver
+
+import (
+	"math"
+
+	"github.com/noTirT/hayday-optimizer/base"
+)
+
+const (
+	epsilon       = 1e-9
+	maxIterations = 100000
+)
+
+// Single row of the form Coefficients * x <= Bound
+type Constraint struct {
+	Coefficients []float64
+	Bound        float64
+}
+
+// Maximize Objective * x subject to all constraints and x >= 0
+type Problem struct {
+	Objective   []float64
+	Constraints []Constraint
+}
+
+type Solution struct {
+	Values    []float64
+	Objective float64
+	// False when a search limit was hit before optimality could be proven
+	Optimal bool
+}
+
+type tableau struct {
+	rows       [][]float64
+	objective  []float64
+	basis      []int
+	variables  int
+	artificial int
+}
+
+// Solve the linear relaxation with the two-phase simplex method
+func SolveLP(problem Problem) (Solution, error) {
+	t := newTableau(problem)
+
+	if t.artificial > 0 {
+		if err := t.phaseOne(); err != nil {
+			return Solution{}, err
+		}
+	}
+
+	t.setObjective(problem.Objective)
+	if err := t.optimize(); err != nil {
+		return Solution{}, err
+	}
+
+	values := make([]float64, len(problem.Objective))
+	for i, column := range t.basis {
+		if column < len(values) {
+			values[column] = t.rhs(i)
+		}
+	}
+
+	return Solution{
+		Values:    values,
+		Objective: t.objective[len(t.objective)-1],
+		Optimal:   true,
+	}, nil
+}
+
+func newTableau(problem Problem) *tableau {
+	n := len(problem.Objective)
+	m := len(problem.Constraints)
+
+	artificial := 0
+	for _, constraint := range problem.Constraints {
+		if constraint.Bound < 0 {
+			artificial++
+		}
+	}
+
+	// Structural columns, one slack per row, artificials and the right hand side
+	width := n + m + artificial + 1
+	t := &tableau{
+		rows:       make([][]float64, m),
+		objective:  make([]float64, width),
+		basis:      make([]int, m),
+		variables:  n,
+		artificial: artificial,
+	}
+
+	nextArtificial := n + m
+	for i, constraint := range problem.Constraints {
+		row := make([]float64, width)
+		sign := 1.0
+		// Rows with a negative bound are flipped to >= and need an artificial start
+		if constraint.Bound < 0 {
+			sign = -1.0
+		}
+		for j, coefficient := range constraint.Coefficients {
+			row[j] = sign * coefficient
+		}
+		row[n+i] = sign
+		row[width-1] = sign * constraint.Bound
+
+		if constraint.Bound < 0 {
+			row[nextArtificial] = 1
+			t.basis[i] = nextArtificial
+			nextArtificial++
+		} else {
+			t.basis[i] = n + i
+		}
+		t.rows[i] = row
+	}
+
+	return t
+}
+
+func (t *tableau) width() int {
+	return len(t.objective)
+}
+
+func (t *tableau) rhs(row int) float64 {
+	return t.rows[row][t.width()-1]
+}
+
+func (t *tableau) isArtificial(column int) bool {
+	return column >= t.width()-1-t.artificial && column < t.width()-1
+}
+
+// Find any feasible basis by driving the artificial variables to zero
+func (t *tableau) phaseOne() error {
+	for j := range t.objective {
+		t.objective[j] = 0
+	}
+	for j := t.width() - 1 - t.artificial; j < t.width()-1; j++ {
+		t.objective[j] = 1
+	}
+	for i, column := range t.basis {
+		if t.isArtificial(column) {
+			for j := range t.objective {
+				t.objective[j] -= t.rows[i][j]
+			}
+		}
+	}
+
+	if err := t.iterate(true); err != nil {
+		return err
+	}
+
+	if t.objective[t.width()-1] < -1e-7 {
+		return base.ErrProblemInfeasible
+	}
+
+	// Pivot artificials that stayed basic at zero level out of the basis
+	for i, column := range t.basis {
+		if !t.isArtificial(column) {
+			continue
+		}
+		for j := 0; j < t.width()-1-t.artificial; j++ {
+			if math.Abs(t.rows[i][j]) > epsilon {
+				t.pivot(i, j)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *tableau) setObjective(objective []float64) {
+	for j := range t.objective {
+		t.objective[j] = 0
+	}
+	for j, coefficient := range objective {
+		t.objective[j] = -coefficient
+	}
+	for i, column := range t.basis {
+		if factor := t.objective[column]; factor != 0 {
+			for j := range t.objective {
+				t.objective[j] -= factor * t.rows[i][j]
+			}
+		}
+	}
+}
+
+func (t *tableau) optimize() error {
+	return t.iterate(false)
+}
+
+func (t *tableau) iterate(allowArtificial bool) error {
+	for iteration := 0; iteration < maxIterations; iteration++ {
+		// Bland's rule: smallest improving column keeps degenerate problems from cycling
+		entering := -1
+		for j := 0; j < t.width()-1; j++ {
+			if !allowArtificial && t.isArtificial(j) {
+				continue
+			}
+			if t.objective[j] < -epsilon {
+				entering = j
+				break
+			}
+		}
+		if entering == -1 {
+			return nil
+		}
+
+		leaving := -1
+		bestRatio := math.Inf(1)
+		for i, row := range t.rows {
+			if row[entering] <= epsilon {
+				continue
+			}
+			ratio := t.rhs(i) / row[entering]
+			if ratio < bestRatio-epsilon || (math.Abs(ratio-bestRatio) <= epsilon && t.basis[i] < t.basis[leaving]) {
+				bestRatio = ratio
+				leaving = i
+			}
+		}
+		if leaving == -1 {
+			return base.ErrProblemUnbounded
+		}
+
+		t.pivot(leaving, entering)
+	}
+
+	return base.ErrSolverIterationLimit
+}
+
+func (t *tableau) pivot(row, column int) {
+	pivotRow := t.rows[row]
+	factor := pivotRow[column]
+	for j := range pivotRow {
+		pivotRow[j] /= factor
+	}
+
+	eliminate := func(target []float64) {
+		multiplier := target[column]
+		if multiplier == 0 {
+			return
+		}
+		for j, value := range pivotRow {
+			if value != 0 {
+				target[j] -= multiplier * value
+			}
+		}
+	}
+
+	for i, other := range t.rows {
+		if i != row {
+			eliminate(other)
+		}
+	}
+	eliminate(t.objective)
+
+	t.basis[row] = column
+}
@@ -0,0 +1,103 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/noTirT/hayday-optimizer/base"
+)
+
+func assertValues(t *testing.T, solution Solution, objective float64, values []float64) {
+	t.Helper()
+
+	if math.Abs(solution.Objective-objective) > 1e-6 {
+		t.Errorf("objective = %v, want %v", solution.Objective, objective)
+	}
+	for j, want := range values {
+		if math.Abs(solution.Values[j]-want) > 1e-6 {
+			t.Errorf("x%d = %v, want %v", j, solution.Values[j], want)
+		}
+	}
+}
+
+func TestSolveLPKnownOptimum(t *testing.T) {
+	// Maximize 3x + 5y with x <= 4, 2y <= 12 and 3x + 2y <= 18
+	solution, err := SolveLP(Problem{
+		Objective: []float64{3, 5},
+		Constraints: []Constraint{
+			{Coefficients: []float64{1, 0}, Bound: 4},
+			{Coefficients: []float64{0, 2}, Bound: 12},
+			{Coefficients: []float64{3, 2}, Bound: 18},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertValues(t, solution, 36, []float64{2, 6})
+	if !solution.Optimal {
+		t.Error("LP solution not marked optimal")
+	}
+}
+
+func TestSolveLPInfeasible(t *testing.T) {
+	// x <= 1 and x >= 2
+	_, err := SolveLP(Problem{
+		Objective: []float64{1},
+		Constraints: []Constraint{
+			{Coefficients: []float64{1}, Bound: 1},
+			{Coefficients: []float64{-1}, Bound: -2},
+		},
+	})
+	if !errors.Is(err, base.ErrProblemInfeasible) {
+		t.Fatalf("err = %v, want %v", err, base.ErrProblemInfeasible)
+	}
+}
+
+func TestSolveLPUnbounded(t *testing.T) {
+	// x - y <= 1 lets both grow together without limit
+	_, err := SolveLP(Problem{
+		Objective: []float64{1, 1},
+		Constraints: []Constraint{
+			{Coefficients: []float64{1, -1}, Bound: 1},
+		},
+	})
+	if !errors.Is(err, base.ErrProblemUnbounded) {
+		t.Fatalf("err = %v, want %v", err, base.ErrProblemUnbounded)
+	}
+}
+
+func TestSolveLPDegenerateDoesNotCycle(t *testing.T) {
+	// Beale's example, the textbook rule of picking the most negative column
+	// cycles on it forever
+	solution, err := SolveLP(Problem{
+		Objective: []float64{0.75, -150, 0.02, -6},
+		Constraints: []Constraint{
+			{Coefficients: []float64{0.25, -60, -0.04, 9}, Bound: 0},
+			{Coefficients: []float64{0.5, -90, -0.02, 3}, Bound: 0},
+			{Coefficients: []float64{0, 0, 1, 0}, Bound: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertValues(t, solution, 0.05, []float64{0.04, 0, 1, 0})
+}
+
+func TestSolveLPPhaseOne(t *testing.T) {
+	// Minimize 2x + 3y with x + y >= 4 and x <= 3, the origin is not feasible
+	solution, err := SolveLP(Problem{
+		Objective: []float64{-2, -3},
+		Constraints: []Constraint{
+			{Coefficients: []float64{-1, -1}, Bound: -4},
+			{Coefficients: []float64{1, 0}, Bound: 3},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertValues(t, solution, -9, []float64{3, 1})
+}